@@ -0,0 +1,412 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/json/v2"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var _ CacheAuditor = (*cacheAuditorImpl)(nil)
+
+// CacheDiscrepancyType is the type of CacheDiscrepancy.
+type CacheDiscrepancyType int
+
+const (
+	// CacheDiscrepancyMissing indicates that an entity exists in Discord but is not cached.
+	CacheDiscrepancyMissing CacheDiscrepancyType = iota
+	// CacheDiscrepancyStale indicates that the cached entity differs from the one in Discord.
+	CacheDiscrepancyStale
+	// CacheDiscrepancyOrphaned indicates that an entity is cached but no longer exists in Discord.
+	CacheDiscrepancyOrphaned
+)
+
+func (t CacheDiscrepancyType) String() string {
+	switch t {
+	case CacheDiscrepancyMissing:
+		return "missing"
+	case CacheDiscrepancyStale:
+		return "stale"
+	case CacheDiscrepancyOrphaned:
+		return "orphaned"
+	}
+	return "unknown"
+}
+
+// CacheDiscrepancy is a difference between the cache.Caches and Discord found by the CacheAuditor.
+type CacheDiscrepancy struct {
+	// Flag is the cache.Flags of the cache the entity belongs to.
+	Flag cache.Flags
+	Type CacheDiscrepancyType
+	// ID is the ID of the entity. For members this is the user ID.
+	ID snowflake.ID
+}
+
+// CacheAuditReport is the result of auditing the caches of a guild.
+type CacheAuditReport struct {
+	GuildID snowflake.ID
+	// Flags are the caches which have been audited successfully.
+	Flags         cache.Flags
+	Discrepancies []CacheDiscrepancy
+	// Repaired is whether the Discrepancies have been repaired.
+	Repaired bool
+	Duration time.Duration
+}
+
+// Consistent returns whether no discrepancies have been found.
+func (r CacheAuditReport) Consistent() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Count returns the number of discrepancies of the given cache.
+func (r CacheAuditReport) Count(flag cache.Flags) int {
+	var count int
+	for _, discrepancy := range r.Discrepancies {
+		if discrepancy.Flag == flag {
+			count++
+		}
+	}
+	return count
+}
+
+// NewCacheAuditor returns a new CacheAuditor with the CacheAuditorConfigOpt(s) applied.
+func NewCacheAuditor(client *Client, opts ...CacheAuditorConfigOpt) CacheAuditor {
+	cfg := defaultCacheAuditorConfig()
+	cfg.apply(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &cacheAuditorImpl{
+		client: client,
+		config: cfg,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// CacheAuditor detects and repairs discrepancies between the cache.Caches and Discord, for example after missed gateway events.
+// It fetches the authoritative state of a guild via rest.Rest and compares it entity by entity with the cache.
+// Guilds can be audited on demand or periodically in the background.
+type CacheAuditor interface {
+	// Audit compares the caches of the given guild with Discord without changing them.
+	// If some caches could not be fetched, the report contains the others and an error is returned.
+	Audit(ctx context.Context, guildID snowflake.ID) (CacheAuditReport, error)
+
+	// Resync compares the caches of the given guild with Discord and repairs all discrepancies.
+	// Missing and stale entities are added to the cache, orphaned entities are removed.
+	Resync(ctx context.Context, guildID snowflake.ID) (CacheAuditReport, error)
+
+	// Start starts auditing all cached guilds in the configured interval.
+	Start()
+
+	// Close stops the periodic audits and waits for a running audit to finish or the context to be done.
+	Close(ctx context.Context)
+}
+
+type cacheAuditorImpl struct {
+	client *Client
+	config cacheAuditorConfig
+
+	startOnce sync.Once
+	wg        sync.WaitGroup
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func (a *cacheAuditorImpl) Audit(ctx context.Context, guildID snowflake.ID) (CacheAuditReport, error) {
+	return a.audit(ctx, guildID, false)
+}
+
+func (a *cacheAuditorImpl) Resync(ctx context.Context, guildID snowflake.ID) (CacheAuditReport, error) {
+	return a.audit(ctx, guildID, true)
+}
+
+func (a *cacheAuditorImpl) Start() {
+	if a.config.Interval <= 0 {
+		return
+	}
+	a.startOnce.Do(func() {
+		a.wg.Add(1)
+		go a.loop()
+	})
+}
+
+func (a *cacheAuditorImpl) Close(ctx context.Context) {
+	a.cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (a *cacheAuditorImpl) loop() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			a.auditAll()
+		}
+	}
+}
+
+func (a *cacheAuditorImpl) auditAll() {
+	var guildIDs []snowflake.ID
+	for guild := range a.client.Caches.Guilds() {
+		guildIDs = append(guildIDs, guild.ID)
+	}
+	for _, guildID := range guildIDs {
+		if a.ctx.Err() != nil {
+			return
+		}
+		report, err := a.audit(a.ctx, guildID, a.config.Repair)
+		if err != nil {
+			a.config.Logger.Error("failed to audit guild caches", slog.Any("err", err), slog.String("guild_id", guildID.String()))
+		}
+		if a.config.ReportFunc != nil {
+			a.config.ReportFunc(report)
+			continue
+		}
+		if !report.Consistent() {
+			a.config.Logger.Warn("found cache discrepancies",
+				slog.String("guild_id", guildID.String()),
+				slog.Int("discrepancies", len(report.Discrepancies)),
+				slog.Bool("repaired", report.Repaired),
+			)
+		}
+	}
+}
+
+func (a *cacheAuditorImpl) audit(ctx context.Context, guildID snowflake.ID, repair bool) (CacheAuditReport, error) {
+	start := time.Now()
+	caches := a.client.Caches
+	flags := a.config.Flags & caches.CacheFlags()
+	report := CacheAuditReport{
+		GuildID:  guildID,
+		Repaired: repair,
+	}
+
+	var errs []error
+	run := func(flag cache.Flags, f func() ([]CacheDiscrepancy, error)) {
+		if flags.Missing(flag) || ctx.Err() != nil {
+			return
+		}
+		discrepancies, err := f()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		report.Flags = report.Flags.Add(flag)
+		report.Discrepancies = append(report.Discrepancies, discrepancies...)
+	}
+
+	run(cache.FlagChannels, func() ([]CacheDiscrepancy, error) {
+		channels, err := a.client.Rest.GetGuildChannels(guildID, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch channels: %w", err)
+		}
+		// threads are not returned by the channels endpoint
+		cached := filterSeq(caches.ChannelsForGuild(guildID), func(channel discord.GuildChannel) bool {
+			_, ok := channel.(discord.GuildThread)
+			return !ok
+		})
+		return diffEntities(cache.FlagChannels, channels, cached, discord.GuildChannel.ID, repair, caches.AddChannel, func(channelID snowflake.ID) {
+			caches.RemoveChannel(channelID)
+		}, "last_message_id", "last_pin_timestamp"), nil
+	})
+
+	run(cache.FlagRoles, func() ([]CacheDiscrepancy, error) {
+		roles, err := a.client.Rest.GetRoles(guildID, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch roles: %w", err)
+		}
+		return diffEntities(cache.FlagRoles, roles, caches.Roles(guildID), func(role discord.Role) snowflake.ID {
+			return role.ID
+		}, repair, caches.AddRole, func(roleID snowflake.ID) {
+			caches.RemoveRole(guildID, roleID)
+		}), nil
+	})
+
+	run(cache.FlagEmojis, func() ([]CacheDiscrepancy, error) {
+		emojis, err := a.client.Rest.GetEmojis(guildID, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch emojis: %w", err)
+		}
+		return diffEntities(cache.FlagEmojis, emojis, caches.Emojis(guildID), func(emoji discord.Emoji) snowflake.ID {
+			return emoji.ID
+		}, repair, caches.AddEmoji, func(emojiID snowflake.ID) {
+			caches.RemoveEmoji(guildID, emojiID)
+		}, "user"), nil
+	})
+
+	run(cache.FlagStickers, func() ([]CacheDiscrepancy, error) {
+		stickers, err := a.client.Rest.GetStickers(guildID, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch stickers: %w", err)
+		}
+		return diffEntities(cache.FlagStickers, stickers, caches.Stickers(guildID), func(sticker discord.Sticker) snowflake.ID {
+			return sticker.ID
+		}, repair, caches.AddSticker, func(stickerID snowflake.ID) {
+			caches.RemoveSticker(guildID, stickerID)
+		}, "user"), nil
+	})
+
+	run(cache.FlagGuildScheduledEvents, func() ([]CacheDiscrepancy, error) {
+		guildScheduledEvents, err := a.client.Rest.GetGuildScheduledEvents(guildID, false, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch guild scheduled events: %w", err)
+		}
+		return diffEntities(cache.FlagGuildScheduledEvents, guildScheduledEvents, caches.GuildScheduledEvents(guildID), func(guildScheduledEvent discord.GuildScheduledEvent) snowflake.ID {
+			return guildScheduledEvent.ID
+		}, repair, caches.AddGuildScheduledEvent, func(guildScheduledEventID snowflake.ID) {
+			caches.RemoveGuildScheduledEvent(guildID, guildScheduledEventID)
+		}, "creator", "user_count"), nil
+	})
+
+	run(cache.FlagMembers, func() ([]CacheDiscrepancy, error) {
+		members, err := a.fetchMembers(ctx, guildID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch members: %w", err)
+		}
+		members = slices.DeleteFunc(members, func(member discord.Member) bool {
+			return !a.config.MemberPolicy(member)
+		})
+		for i := range members {
+			members[i].RoleIDs = slices.Sorted(slices.Values(members[i].RoleIDs))
+		}
+		cached := func(yield func(discord.Member) bool) {
+			for member := range caches.Members(guildID) {
+				member.RoleIDs = slices.Sorted(slices.Values(member.RoleIDs))
+				if !yield(member) {
+					return
+				}
+			}
+		}
+		// the user and voice fields are not sent in all member updates
+		return diffEntities(cache.FlagMembers, members, cached, func(member discord.Member) snowflake.ID {
+			return member.User.ID
+		}, repair, caches.AddMember, func(userID snowflake.ID) {
+			caches.RemoveMember(guildID, userID)
+		}, "user", "deaf", "mute"), nil
+	})
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	report.Duration = time.Since(start)
+	return report, errors.Join(errs...)
+}
+
+func (a *cacheAuditorImpl) fetchMembers(ctx context.Context, guildID snowflake.ID) ([]discord.Member, error) {
+	var (
+		members []discord.Member
+		after   snowflake.ID
+	)
+	for {
+		page, err := a.client.Rest.GetMembers(guildID, 1000, after, rest.WithCtx(ctx))
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, page...)
+		if len(page) < 1000 {
+			return members, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+// diffEntities compares the fetched with the cached entities by their JSON representation without the given keys and optionally repairs the cache.
+func diffEntities[T any](flag cache.Flags, fetched []T, cached iter.Seq[T], idFunc func(T) snowflake.ID, repair bool, add func(T), remove func(snowflake.ID), ignoredKeys ...string) []CacheDiscrepancy {
+	fetchedByID := make(map[snowflake.ID]T, len(fetched))
+	for _, entity := range fetched {
+		fetchedByID[idFunc(entity)] = entity
+	}
+
+	var discrepancies []CacheDiscrepancy
+	seen := map[snowflake.ID]struct{}{}
+	for entity := range cached {
+		id := idFunc(entity)
+		seen[id] = struct{}{}
+		fetchedEntity, ok := fetchedByID[id]
+		if !ok {
+			discrepancies = append(discrepancies, CacheDiscrepancy{Flag: flag, Type: CacheDiscrepancyOrphaned, ID: id})
+			continue
+		}
+		if !equalJSON(entity, fetchedEntity, ignoredKeys) {
+			discrepancies = append(discrepancies, CacheDiscrepancy{Flag: flag, Type: CacheDiscrepancyStale, ID: id})
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(fetchedByID)) {
+		if _, ok := seen[id]; !ok {
+			discrepancies = append(discrepancies, CacheDiscrepancy{Flag: flag, Type: CacheDiscrepancyMissing, ID: id})
+		}
+	}
+
+	if repair {
+		for _, discrepancy := range discrepancies {
+			if discrepancy.Type == CacheDiscrepancyOrphaned {
+				remove(discrepancy.ID)
+				continue
+			}
+			add(fetchedByID[discrepancy.ID])
+		}
+	}
+	return discrepancies
+}
+
+func equalJSON(a any, b any, ignoredKeys []string) bool {
+	aMap, err := toJSONMap(a, ignoredKeys)
+	if err != nil {
+		return false
+	}
+	bMap, err := toJSONMap(b, ignoredKeys)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aMap, bMap)
+}
+
+func toJSONMap(v any, ignoredKeys []string) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for _, key := range ignoredKeys {
+		delete(m, key)
+	}
+	return m, nil
+}
+
+func filterSeq[T any](seq iter.Seq[T], filter func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if filter(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+)
+
+func defaultCacheAuditorConfig() cacheAuditorConfig {
+	return cacheAuditorConfig{
+		Logger:       slog.Default(),
+		Flags:        cache.FlagChannels | cache.FlagRoles | cache.FlagMembers | cache.FlagEmojis | cache.FlagStickers | cache.FlagGuildScheduledEvents,
+		MemberPolicy: cache.PolicyAll[discord.Member],
+		Interval:     time.Hour,
+	}
+}
+
+type cacheAuditorConfig struct {
+	Logger       *slog.Logger
+	Flags        cache.Flags
+	MemberPolicy cache.Policy[discord.Member]
+	Interval     time.Duration
+	Repair       bool
+	ReportFunc   func(report CacheAuditReport)
+}
+
+// CacheAuditorConfigOpt is a functional option for configuring a CacheAuditor.
+type CacheAuditorConfigOpt func(config *cacheAuditorConfig)
+
+func (c *cacheAuditorConfig) apply(opts []CacheAuditorConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_cache_auditor"))
+}
+
+// WithCacheAuditorLogger overrides the default Logger in the cacheAuditorConfig.
+func WithCacheAuditorLogger(logger *slog.Logger) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithCacheAuditorFlags sets which caches are audited. Caches which are disabled in the cache.Caches are always skipped.
+// Supported are cache.FlagChannels, cache.FlagRoles, cache.FlagMembers, cache.FlagEmojis, cache.FlagStickers and cache.FlagGuildScheduledEvents, which are all audited by default.
+// Auditing members fetches all members of a guild page by page, which is slow for large guilds and requires the gateway.IntentGuildMembers.
+func WithCacheAuditorFlags(flags cache.Flags) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.Flags = flags
+	}
+}
+
+// WithCacheAuditorMemberPolicy sets which members are expected to be cached.
+// This should be the same cache.Policy as your cache.WithMemberCachePolicy, otherwise members which are never cached are reported as missing.
+func WithCacheAuditorMemberPolicy(policy cache.Policy[discord.Member]) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.MemberPolicy = policy
+	}
+}
+
+// WithCacheAuditorInterval sets how often all cached guilds are audited after CacheAuditor.Start has been called.
+func WithCacheAuditorInterval(interval time.Duration) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.Interval = interval
+	}
+}
+
+// WithCacheAuditorRepair sets whether periodic audits repair the discrepancies they find. This is disabled by default.
+func WithCacheAuditorRepair(repair bool) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.Repair = repair
+	}
+}
+
+// WithCacheAuditorReportFunc sets the func which receives the CacheAuditReport of every periodic audit.
+// By default, reports with discrepancies are logged.
+func WithCacheAuditorReportFunc(f func(report CacheAuditReport)) CacheAuditorConfigOpt {
+	return func(config *cacheAuditorConfig) {
+		config.ReportFunc = f
+	}
+}
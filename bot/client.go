@@ -30,25 +30,14 @@ type Client struct {
 	VoiceManager          voice.Manager
 	Caches                cache.Caches
 	MemberChunkingManager MemberChunkingManager
+	MemberBackfiller      MemberBackfiller
 	WebhookManager        WebhookManager
-}
+	Scheduler             Scheduler
+	PresenceAggregator    PresenceAggregator
+	VoiceTracker          VoiceTracker
+	CacheAuditor          CacheAuditor
 
-func (c *Client) Close(ctx context.Context) {
-	if c.VoiceManager != nil {
-		c.VoiceManager.Close(ctx)
-	}
-	if c.Gateway != nil {
-		c.Gateway.Close(ctx)
-	}
-	if c.Rest != nil {
-		c.Rest.Close(ctx)
-	}
-	if c.ShardManager != nil {
-		c.ShardManager.Close(ctx)
-	}
-	if c.HTTPServer != nil {
-		c.HTTPServer.Close(ctx)
-	}
+	work workTracker
 }
 
 func (c *Client) ID() snowflake.ID {
@@ -0,0 +1,250 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/sharding"
+)
+
+// Work kinds tracked by Client.TrackWork.
+const (
+	// WorkKindEventListener is used for EventListener(s) like handler.Mux called by the default EventManager, synchronously or asynchronously because of WithAsyncEventsEnabled.
+	WorkKindEventListener = "event_listener"
+	// WorkKindInteractionHandler is used for interaction handlers running in their own goroutine.
+	WorkKindInteractionHandler = "interaction_handler"
+	// WorkKindScheduledTask is used for running ScheduledTask(s) of the Scheduler.
+	WorkKindScheduledTask = "scheduled_task"
+)
+
+// ErrShuttingDown is returned when new work is refused because Client.Shutdown or Client.Close was called.
+var ErrShuttingDown = errors.New("client is shutting down")
+
+// ShutdownReport describes what happened during Client.Shutdown.
+type ShutdownReport struct {
+	// Duration is how long the shutdown took.
+	Duration time.Duration
+	// AbandonedWork is the number of tracked work items per kind which were still running when the context was done.
+	AbandonedWork map[string]int
+	// AbandonedEvents is the number of queued events a WorkerPoolEventManager did not handle before the context was done.
+	AbandonedEvents int
+	// ShardStates are the session states of all shards at the time intake was stopped.
+	// Pass them to sharding.WithShardIDsWithStates or gateway.WithSessionID & gateway.WithSequence to resume the sessions on the next start.
+	ShardStates map[int]sharding.ShardState
+}
+
+// Abandoned returns whether any work or events were abandoned during the shutdown.
+func (r ShutdownReport) Abandoned() bool {
+	return len(r.AbandonedWork) > 0 || r.AbandonedEvents > 0
+}
+
+// IsShuttingDown returns whether Client.Shutdown or Client.Close was called.
+// Code starting new goroutines for events or interactions should check it and refuse new work while the Client drains.
+func (c *Client) IsShuttingDown() bool {
+	return c.work.isShuttingDown()
+}
+
+// TrackWork registers a unit of in-flight work of the given kind which Client.Shutdown waits for.
+// The returned func must be called once the work is done.
+// This should be used for all goroutines handling events or interactions outside the EventManager.
+func (c *Client) TrackWork(kind string) func() {
+	return c.work.add(kind)
+}
+
+// Shutdown gracefully shuts down the Client in the following order:
+//  1. stop intake: voice connections are closed, the httpserver.Server stops accepting requests and the Gateway/shards are disconnected without invalidating their sessions
+//  2. drain: wait for the Scheduler, MemberBackfiller, CacheAuditor, WorkerPoolEventManager and all tracked work to finish
+//  3. save the resume state of all shards into the ShutdownReport
+//  4. close the transports: Gateway, ShardManager and rest.Rest
+//
+// Waiting in step 2 stops once the context is done. Work which is still running at that point is reported as abandoned.
+// When Shutdown is called from a synchronous EventListener, it waits for that EventListener as well, so the context should have a deadline.
+func (c *Client) Shutdown(ctx context.Context) ShutdownReport {
+	start := time.Now()
+	c.work.shutdown()
+
+	// stop intake
+	if c.VoiceManager != nil {
+		c.VoiceManager.Close(ctx)
+	}
+	var httpServerClosed chan struct{}
+	if c.HTTPServer != nil {
+		// shutting down the http server waits for in-flight interactions to be responded to
+		httpServerClosed = make(chan struct{})
+		go func() {
+			defer close(httpServerClosed)
+			c.HTTPServer.Close(ctx)
+		}()
+	}
+	for shard := range c.shards() {
+		shard.CloseWithCode(ctx, websocket.CloseServiceRestart, "Shutting down")
+	}
+
+	// drain
+	if c.Scheduler != nil {
+		c.Scheduler.Close(ctx)
+	}
+	if c.MemberBackfiller != nil {
+		c.MemberBackfiller.Close(ctx)
+	}
+	if c.CacheAuditor != nil {
+		c.CacheAuditor.Close(ctx)
+	}
+	report := ShutdownReport{}
+	if eventManager, ok := c.EventManager.(WorkerPoolEventManager); ok {
+		eventManager.Close(ctx)
+		report.AbandonedEvents = eventManager.Metrics().Queued
+	}
+	report.AbandonedWork = c.work.wait(ctx)
+	if httpServerClosed != nil {
+		<-httpServerClosed
+	}
+
+	// save shard states
+	for shard := range c.shards() {
+		sessionID := shard.SessionID()
+		sequence := shard.LastSequenceReceived()
+		if sessionID == nil || sequence == nil {
+			continue
+		}
+		state := sharding.ShardState{
+			SessionID: *sessionID,
+			Sequence:  *sequence,
+		}
+		if resumeURL := shard.ResumeURL(); resumeURL != nil {
+			state.ResumeURL = *resumeURL
+		}
+		if report.ShardStates == nil {
+			report.ShardStates = map[int]sharding.ShardState{}
+		}
+		report.ShardStates[shard.ShardID()] = state
+	}
+
+	// close transports
+	if c.Gateway != nil {
+		c.Gateway.Close(ctx)
+	}
+	if c.ShardManager != nil {
+		c.ShardManager.Close(ctx)
+	}
+	if c.Rest != nil {
+		c.Rest.Close(ctx)
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+// Close shuts down the Client via Shutdown and logs a warning if any work was abandoned.
+func (c *Client) Close(ctx context.Context) {
+	report := c.Shutdown(ctx)
+	if report.Abandoned() {
+		c.Logger.Warn("client closed before all work was done",
+			slog.Any("abandoned_work", report.AbandonedWork),
+			slog.Int("abandoned_events", report.AbandonedEvents),
+			slog.Duration("duration", report.Duration),
+		)
+	}
+}
+
+func (c *Client) shards() iter.Seq[gateway.Gateway] {
+	return func(yield func(gateway.Gateway) bool) {
+		if c.Gateway != nil {
+			yield(c.Gateway)
+			return
+		}
+		if c.ShardManager != nil {
+			for shard := range c.ShardManager.Shards() {
+				if !yield(shard) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// workTracker counts in-flight work per kind and lets Client.Shutdown wait for it.
+type workTracker struct {
+	mu           sync.Mutex
+	shuttingDown bool
+	total        int
+	kinds        map[string]int
+	idle         chan struct{}
+}
+
+func (t *workTracker) add(kind string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.kinds == nil {
+		t.kinds = map[string]int{}
+	}
+	t.kinds[kind]++
+	t.total++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.kinds[kind]--
+			if t.kinds[kind] == 0 {
+				delete(t.kinds, kind)
+			}
+			t.total--
+			if t.total == 0 && t.idle != nil {
+				close(t.idle)
+				t.idle = nil
+			}
+		})
+	}
+}
+
+func (t *workTracker) shutdown() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.shuttingDown = true
+}
+
+func (t *workTracker) isShuttingDown() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.shuttingDown
+}
+
+// wait waits until no work is in-flight or the context is done and returns the abandoned work per kind.
+func (t *workTracker) wait(ctx context.Context) map[string]int {
+	t.mu.Lock()
+	if t.total == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.total == 0 {
+		return nil
+	}
+	abandoned := make(map[string]int, len(t.kinds))
+	for kind, count := range t.kinds {
+		abandoned[kind] = count
+	}
+	return abandoned
+}
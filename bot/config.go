@@ -51,7 +51,22 @@ type config struct {
 	MemberChunkingManager MemberChunkingManager
 	MemberChunkingFilter  MemberChunkingFilter
 
+	MemberBackfiller           MemberBackfiller
+	MemberBackfillerConfigOpts []MemberBackfillerConfigOpt
+	MemberBackfillerEnabled    bool
+
 	WebhookManager WebhookManager
+
+	Scheduler           Scheduler
+	SchedulerConfigOpts []SchedulerConfigOpt
+	SchedulerEnabled    bool
+
+	PresenceAggregator PresenceAggregator
+	VoiceTracker       VoiceTracker
+
+	CacheAuditor           CacheAuditor
+	CacheAuditorConfigOpts []CacheAuditorConfigOpt
+	CacheAuditorEnabled    bool
 }
 
 // ConfigOpt is a type alias for a function that takes a config and is used to configure your Client.
@@ -208,12 +223,73 @@ func WithMemberChunkingFilter(memberChunkingFilter MemberChunkingFilter) ConfigO
 	}
 }
 
+// WithMemberBackfiller lets you inject your own MemberBackfiller.
+func WithMemberBackfiller(memberBackfiller MemberBackfiller) ConfigOpt {
+	return func(config *config) {
+		config.MemberBackfiller = memberBackfiller
+	}
+}
+
+// WithMemberBackfillerConfigOpts enables the default MemberBackfiller and lets you configure it. It also enables the MemberBackfiller when called without options.
+func WithMemberBackfillerConfigOpts(opts ...MemberBackfillerConfigOpt) ConfigOpt {
+	return func(config *config) {
+		config.MemberBackfillerEnabled = true
+		config.MemberBackfillerConfigOpts = append(config.MemberBackfillerConfigOpts, opts...)
+	}
+}
+
 func WithWebhookManager(webhookManager WebhookManager) ConfigOpt {
 	return func(config *config) {
 		config.WebhookManager = webhookManager
 	}
 }
 
+// WithScheduler lets you inject your own Scheduler.
+func WithScheduler(scheduler Scheduler) ConfigOpt {
+	return func(config *config) {
+		config.Scheduler = scheduler
+	}
+}
+
+// WithSchedulerConfigOpts enables the default Scheduler and lets you configure it. It also enables the Scheduler when called without options.
+func WithSchedulerConfigOpts(opts ...SchedulerConfigOpt) ConfigOpt {
+	return func(config *config) {
+		config.SchedulerEnabled = true
+		config.SchedulerConfigOpts = append(config.SchedulerConfigOpts, opts...)
+	}
+}
+
+// WithPresenceAggregator sets the PresenceAggregator which is kept up to date with the presences of all guilds.
+// Use NewPresenceAggregator to create the default implementation.
+func WithPresenceAggregator(presenceAggregator PresenceAggregator) ConfigOpt {
+	return func(config *config) {
+		config.PresenceAggregator = presenceAggregator
+	}
+}
+
+// WithVoiceTracker sets the VoiceTracker which is kept up to date with the voice states of all guilds.
+// Use NewVoiceTracker to create the default implementation.
+func WithVoiceTracker(voiceTracker VoiceTracker) ConfigOpt {
+	return func(config *config) {
+		config.VoiceTracker = voiceTracker
+	}
+}
+
+// WithCacheAuditor lets you inject your own CacheAuditor.
+func WithCacheAuditor(cacheAuditor CacheAuditor) ConfigOpt {
+	return func(config *config) {
+		config.CacheAuditor = cacheAuditor
+	}
+}
+
+// WithCacheAuditorConfigOpts enables the default CacheAuditor and lets you configure it. It also enables the CacheAuditor when called without options.
+func WithCacheAuditorConfigOpts(opts ...CacheAuditorConfigOpt) ConfigOpt {
+	return func(config *config) {
+		config.CacheAuditorEnabled = true
+		config.CacheAuditorConfigOpts = append(config.CacheAuditorConfigOpts, opts...)
+	}
+}
+
 func defaultHTTPServerEventHandlerFunc(client *Client) httpserver.EventHandlerFunc {
 	return client.EventManager.HandleHTTPEvent
 }
@@ -350,11 +426,28 @@ func BuildClient(
 	}
 	client.MemberChunkingManager = cfg.MemberChunkingManager
 
+	if cfg.MemberBackfiller == nil && cfg.MemberBackfillerEnabled {
+		cfg.MemberBackfiller = NewMemberBackfiller(client, append([]MemberBackfillerConfigOpt{WithMemberBackfillerLogger(cfg.Logger)}, cfg.MemberBackfillerConfigOpts...)...)
+	}
+	client.MemberBackfiller = cfg.MemberBackfiller
+
 	if cfg.WebhookManager == nil {
 		cfg.WebhookManager = NewWebhookManager(client, cfg.Logger)
 	}
 	client.WebhookManager = cfg.WebhookManager
 
+	if cfg.Scheduler == nil && cfg.SchedulerEnabled {
+		cfg.Scheduler = NewScheduler(client, append([]SchedulerConfigOpt{WithSchedulerLogger(cfg.Logger)}, cfg.SchedulerConfigOpts...)...)
+	}
+	client.Scheduler = cfg.Scheduler
+	client.PresenceAggregator = cfg.PresenceAggregator
+	client.VoiceTracker = cfg.VoiceTracker
+
+	if cfg.CacheAuditor == nil && cfg.CacheAuditorEnabled {
+		cfg.CacheAuditor = NewCacheAuditor(client, append([]CacheAuditorConfigOpt{WithCacheAuditorLogger(cfg.Logger)}, cfg.CacheAuditorConfigOpts...)...)
+	}
+	client.CacheAuditor = cfg.CacheAuditor
+
 	if cfg.Caches == nil {
 		cfg.Caches = cache.New(cfg.CacheConfigOpts...)
 	}
@@ -3,6 +3,9 @@ package bot
 import (
 	"context"
 	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
 )
 
 // WaitForEvent waits for an event passing the filterFunc and then calls the actionFunc. You can cancel this function with the passed context.Context and the cancelFunc gets called then.
@@ -44,3 +47,123 @@ func NewEventCollector[E Event](client *Client, filterFunc func(e E) bool) (<-ch
 		})
 	}
 }
+
+// NewCollector returns a new Collector for events of type E passing the given filter. The filter may be nil.
+func NewCollector[E Event](client *Client, filterFunc func(e E) bool) *Collector[E] {
+	return &Collector[E]{
+		client:     client,
+		filterFunc: filterFunc,
+	}
+}
+
+// Collector collects events of type E passing a filter.
+// Each call to Collect or First registers its own EventListener which is removed automatically once the call returns.
+type Collector[E Event] struct {
+	client     *Client
+	filterFunc func(e E) bool
+}
+
+// Collect collects events until maxEvents events have been collected, no event passed the filter for the idle duration or the context is done.
+// A maxEvents <= 0 collects an unlimited amount of events and an idle <= 0 disables the idle timeout.
+// If the context is done the already collected events are returned together with the context error.
+func (c *Collector[E]) Collect(ctx context.Context, maxEvents int, idle time.Duration) ([]E, error) {
+	var events []E
+	err := c.collect(ctx, idle, func(e E) bool {
+		events = append(events, e)
+		return maxEvents <= 0 || len(events) < maxEvents
+	})
+	return events, err
+}
+
+// First returns the first event passing the filter or the context error if the context is done before.
+func (c *Collector[E]) First(ctx context.Context) (E, error) {
+	var event E
+	err := c.collect(ctx, 0, func(e E) bool {
+		event = e
+		return false
+	})
+	return event, err
+}
+
+// collect calls the given func for each event until it returns false, the idle timeout passed or the context is done.
+func (c *Collector[E]) collect(ctx context.Context, idle time.Duration, f func(e E) bool) error {
+	ch := make(chan E)
+	done := make(chan struct{})
+
+	listener := NewListenerFunc(func(e E) {
+		if c.filterFunc != nil && !c.filterFunc(e) {
+			return
+		}
+		select {
+		case ch <- e:
+		case <-done:
+		}
+	})
+	c.client.EventManager.AddEventListeners(listener)
+	defer func() {
+		c.client.EventManager.RemoveEventListeners(listener)
+		close(done)
+	}()
+
+	var idleTimer *time.Timer
+	var idleC <-chan time.Time
+	if idle > 0 {
+		idleTimer = time.NewTimer(idle)
+		defer idleTimer.Stop()
+		idleC = idleTimer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-idleC:
+			return nil
+		case e := <-ch:
+			if !f(e) {
+				return nil
+			}
+			if idleTimer != nil {
+				idleTimer.Reset(idle)
+			}
+		}
+	}
+}
+
+// Vote is a single vote of a user for a choice. If Remove is true the vote of the user for the choice is withdrawn.
+type Vote[K comparable] struct {
+	UserID snowflake.ID
+	Choice K
+	Remove bool
+}
+
+// TallyVotes collects votes from events of type E until no vote was received for the idle duration or the context is done.
+// The voteFunc converts an event into a Vote and returns false for events which are no votes.
+// Every user counts once per choice, but can vote for multiple choices. The returned map contains the number of votes per choice.
+// If the context is done the tally so far is returned together with the context error.
+func TallyVotes[E Event, K comparable](ctx context.Context, client *Client, idle time.Duration, voteFunc func(e E) (Vote[K], bool)) (map[K]int, error) {
+	voters := make(map[K]map[snowflake.ID]struct{})
+	err := NewCollector[E](client, nil).collect(ctx, idle, func(e E) bool {
+		vote, ok := voteFunc(e)
+		if !ok {
+			return true
+		}
+		if vote.Remove {
+			delete(voters[vote.Choice], vote.UserID)
+			return true
+		}
+		if _, ok = voters[vote.Choice]; !ok {
+			voters[vote.Choice] = make(map[snowflake.ID]struct{})
+		}
+		voters[vote.Choice][vote.UserID] = struct{}{}
+		return true
+	})
+
+	tally := make(map[K]int, len(voters))
+	for choice, users := range voters {
+		if len(users) > 0 {
+			tally[choice] = len(users)
+		}
+	}
+	return tally, err
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// TypedEventListener is an EventListener which only wants to receive events of a specific type.
+// The EventManager uses the type to skip the listener for all other events.
+// Listeners created with NewListenerFunc, NewListenerChan and NewListener implement this interface.
+type TypedEventListener interface {
+	EventListener
+
+	// EventType returns the type of events this listener wants to receive. This can be an interface type.
+	EventType() reflect.Type
+}
+
+// PriorityEventListener is an EventListener with a priority.
+// Listeners with a higher priority are called before listeners with a lower priority. EventListener(s) without a priority have a priority of 0.
+type PriorityEventListener interface {
+	EventListener
+
+	// Priority returns the priority of the listener.
+	Priority() int
+}
+
+// ListenerOpt is a functional option for configuring an EventListener created with NewListener.
+type ListenerOpt func(opts *listenerOpts)
+
+type listenerOpts struct {
+	priority int
+	filter   func(e Event) bool
+}
+
+// ListenerPriority sets the priority of the EventListener.
+func ListenerPriority(priority int) ListenerOpt {
+	return func(opts *listenerOpts) {
+		opts.priority = priority
+	}
+}
+
+// ListenerFilter only calls the EventListener for events passing the given filter.
+func ListenerFilter(filter func(e Event) bool) ListenerOpt {
+	return func(opts *listenerOpts) {
+		opts.filter = filter
+	}
+}
+
+// NewListener returns a new EventListener for the given func(e E) with the ListenerOpt(s) applied.
+func NewListener[E Event](f func(e E), opts ...ListenerOpt) EventListener {
+	var o listenerOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &listener[E]{f: f, opts: o}
+}
+
+type listener[E Event] struct {
+	f    func(e E)
+	opts listenerOpts
+}
+
+func (l *listener[E]) OnEvent(e Event) {
+	event, ok := e.(E)
+	if !ok {
+		return
+	}
+	if l.opts.filter != nil && !l.opts.filter(e) {
+		return
+	}
+	l.f(event)
+}
+
+func (l *listener[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+func (l *listener[E]) Priority() int {
+	return l.opts.priority
+}
+
+type registeredListener struct {
+	listener  EventListener
+	eventType reflect.Type
+	priority  int
+}
+
+// listenerRegistry keeps the EventListener(s) of an EventManager sorted by priority and indexed by event type.
+type listenerRegistry struct {
+	mu        sync.RWMutex
+	listeners []registeredListener
+	index     map[reflect.Type][]EventListener
+}
+
+func newListenerRegistry(listeners []EventListener) *listenerRegistry {
+	r := &listenerRegistry{}
+	r.add(listeners...)
+	return r
+}
+
+func (r *listenerRegistry) add(listeners ...EventListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range listeners {
+		rl := registeredListener{listener: l}
+		if tl, ok := l.(TypedEventListener); ok {
+			rl.eventType = tl.EventType()
+		}
+		if pl, ok := l.(PriorityEventListener); ok {
+			rl.priority = pl.Priority()
+		}
+		r.listeners = append(r.listeners, rl)
+	}
+	slices.SortStableFunc(r.listeners, func(a, b registeredListener) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+	r.index = nil
+}
+
+func (r *listenerRegistry) remove(listeners ...EventListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range listeners {
+		for i, rl := range r.listeners {
+			if rl.listener == l {
+				r.listeners = slices.Delete(r.listeners, i, i+1)
+				break
+			}
+		}
+	}
+	r.index = nil
+}
+
+func (r *listenerRegistry) len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.listeners)
+}
+
+// listenersFor returns all EventListener(s) which want to receive the given event in the order they should be called.
+// The returned slice must not be modified.
+func (r *listenerRegistry) listenersFor(event Event) []EventListener {
+	eventType := reflect.TypeOf(event)
+
+	r.mu.RLock()
+	listeners, ok := r.index[eventType]
+	r.mu.RUnlock()
+	if ok {
+		return listeners
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if listeners, ok = r.index[eventType]; ok {
+		return listeners
+	}
+	for _, rl := range r.listeners {
+		if rl.eventType == nil || eventType.AssignableTo(rl.eventType) {
+			listeners = append(listeners, rl.listener)
+		}
+	}
+	if r.index == nil {
+		r.index = make(map[reflect.Type][]EventListener)
+	}
+	r.index[eventType] = listeners
+	return listeners
+}
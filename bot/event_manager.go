@@ -2,8 +2,12 @@ package bot
 
 import (
 	"log/slog"
+	"reflect"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
+
+	"github.com/disgoorg/snowflake/v2"
 
 	"github.com/disgoorg/disgo/gateway"
 	"github.com/disgoorg/disgo/httpserver"
@@ -16,14 +20,21 @@ func NewEventManager(client *Client, opts ...EventManagerConfigOpt) EventManager
 	cfg := defaultEventManagerConfig()
 	cfg.apply(opts)
 
-	return &eventManagerImpl{
+	if cfg.Workers > 0 {
+		return newWorkerPoolEventManager(client, cfg)
+	}
+
+	m := &eventManagerImpl{
 		client:             client,
 		logger:             cfg.Logger,
-		eventListeners:     cfg.EventListeners,
+		eventListeners:     newListenerRegistry(cfg.EventListeners),
 		asyncEventsEnabled: cfg.AsyncEventsEnabled,
 		gatewayHandlers:    cfg.GatewayHandlers,
 		httpServerHandler:  cfg.HTTPServerHandler,
 	}
+	m.listenersHandler = m.callListeners
+	m.Use(cfg.Middlewares...)
+	return m
 }
 
 // EventManager lets you listen for specific events triggered by raw Gateway events
@@ -40,6 +51,10 @@ type EventManager interface {
 	// HandleHTTPEvent calls the HTTPServerEventHandler for the payload
 	HandleHTTPEvent(respondFunc httpserver.RespondFunc, event httpserver.EventInteractionCreate)
 
+	// Use adds the given EventMiddleware(s) which are executed for every dispatched Event before the EventListener(s) are called.
+	// It is safe to call Use while events are dispatched, events already being handled keep using the previous middlewares.
+	Use(middlewares ...EventMiddleware)
+
 	// DispatchEvent dispatches a new Event to the Client's EventListener(s)
 	DispatchEvent(event Event)
 }
@@ -64,6 +79,10 @@ func (l *listenerFunc[E]) OnEvent(e Event) {
 	}
 }
 
+func (l *listenerFunc[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
 // NewListenerChan returns a new EventListener for the given chan<- Event
 func NewListenerChan[E Event](c chan<- E) EventListener {
 	return &listenerChan[E]{c: c}
@@ -79,6 +98,10 @@ func (l *listenerChan[E]) OnEvent(e Event) {
 	}
 }
 
+func (l *listenerChan[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
 // Event the basic interface each event implement
 type Event interface {
 	Client() *Client
@@ -122,10 +145,17 @@ type HTTPServerEventHandler interface {
 type eventManagerImpl struct {
 	mu sync.Mutex
 
-	client             *Client
-	logger             *slog.Logger
-	eventListenerMu    sync.Mutex
-	eventListeners     []EventListener
+	client *Client
+	logger *slog.Logger
+	// dispatchMu serializes DispatchEvent, so synchronous listeners are never called concurrently
+	dispatchMu     sync.Mutex
+	eventListeners *listenerRegistry
+	middlewaresMu  sync.Mutex
+	middlewares    []EventMiddleware
+	// listenersHandler calls the EventListener(s) at the end of the middleware chain
+	listenersHandler EventHandlerFunc
+	// handler calls the listenersHandler wrapped with the EventMiddleware(s), it is swapped on every call to Use
+	handler            atomic.Pointer[EventHandlerFunc]
 	asyncEventsEnabled bool
 	gatewayHandlers    map[gateway.EventType]GatewayEventHandler
 	httpServerHandler  HTTPServerEventHandler
@@ -148,17 +178,28 @@ func (e *eventManagerImpl) HandleHTTPEvent(respondFunc httpserver.RespondFunc, e
 }
 
 func (e *eventManagerImpl) DispatchEvent(event Event) {
+	e.dispatchMu.Lock()
+	defer e.dispatchMu.Unlock()
+	// synchronous EventListener(s) are tracked as well, so Client.Shutdown waits for them
+	defer e.client.TrackWork(WorkKindEventListener)()
 	defer func() {
 		if r := recover(); r != nil {
 			e.logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(debug.Stack())))
 			return
 		}
 	}()
-	e.eventListenerMu.Lock()
-	defer e.eventListenerMu.Unlock()
-	for _, listener := range e.eventListeners {
-		if e.asyncEventsEnabled {
+	if err := (*e.handler.Load())(event); err != nil {
+		e.logger.Error("error while handling event", slog.String("type", reflect.TypeOf(event).String()), slog.Any("err", err))
+	}
+}
+
+func (e *eventManagerImpl) callListeners(event Event) error {
+	for _, listener := range e.eventListeners.listenersFor(event) {
+		// no new goroutines are started while the client drains, the remaining events are handled synchronously
+		if e.asyncEventsEnabled && !e.client.IsShuttingDown() {
+			done := e.client.TrackWork(WorkKindEventListener)
 			go func() {
+				defer done()
 				defer func() {
 					if r := recover(); r != nil {
 						e.logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(debug.Stack())))
@@ -171,23 +212,76 @@ func (e *eventManagerImpl) DispatchEvent(event Event) {
 		}
 		listener.OnEvent(event)
 	}
+	return nil
+}
+
+func (e *eventManagerImpl) Use(middlewares ...EventMiddleware) {
+	e.middlewaresMu.Lock()
+	defer e.middlewaresMu.Unlock()
+	e.middlewares = append(e.middlewares, middlewares...)
+	handler := chainEventMiddlewares(e.middlewares, e.listenersHandler)
+	e.handler.Store(&handler)
 }
 
 func (e *eventManagerImpl) AddEventListeners(listeners ...EventListener) {
-	e.eventListenerMu.Lock()
-	defer e.eventListenerMu.Unlock()
-	e.eventListeners = append(e.eventListeners, listeners...)
+	e.eventListeners.add(listeners...)
 }
 
 func (e *eventManagerImpl) RemoveEventListeners(listeners ...EventListener) {
-	e.eventListenerMu.Lock()
-	defer e.eventListenerMu.Unlock()
-	for _, listener := range listeners {
-		for i, l := range e.eventListeners {
-			if l == listener {
-				e.eventListeners = append(e.eventListeners[:i], e.eventListeners[i+1:]...)
-				break
+	e.eventListeners.remove(listeners...)
+}
+
+// eventGuildIDFuncs caches a func per event type which reads its GuildID field.
+var eventGuildIDFuncs sync.Map
+
+// EventGuildID returns the guild id of the given event if it belongs to a guild.
+// It looks for a GuildID method or field on the event. The field is looked up once per event type.
+func EventGuildID(event Event) (snowflake.ID, bool) {
+	if e, ok := event.(interface{ GuildID() *snowflake.ID }); ok {
+		if guildID := e.GuildID(); guildID != nil {
+			return *guildID, true
+		}
+		return 0, false
+	}
+
+	v := reflect.ValueOf(event)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return 0, false
+	}
+	v = v.Elem()
+
+	f, ok := eventGuildIDFuncs.Load(v.Type())
+	if !ok {
+		f, _ = eventGuildIDFuncs.LoadOrStore(v.Type(), newEventGuildIDFunc(v.Type()))
+	}
+	return f.(func(v reflect.Value) (snowflake.ID, bool))(v)
+}
+
+// newEventGuildIDFunc returns a func which reads the snowflake.ID or *snowflake.ID GuildID field of the given struct type without allocating.
+func newEventGuildIDFunc(t reflect.Type) func(v reflect.Value) (snowflake.ID, bool) {
+	field, ok := t.FieldByName("GuildID")
+	if !ok || !field.IsExported() {
+		return func(reflect.Value) (snowflake.ID, bool) { return 0, false }
+	}
+	index := field.Index
+	switch field.Type {
+	case reflect.TypeFor[snowflake.ID]():
+		return func(v reflect.Value) (snowflake.ID, bool) {
+			field, err := v.FieldByIndexErr(index)
+			if err != nil {
+				return 0, false
+			}
+			guildID := snowflake.ID(field.Uint())
+			return guildID, guildID != 0
+		}
+	case reflect.TypeFor[*snowflake.ID]():
+		return func(v reflect.Value) (snowflake.ID, bool) {
+			field, err := v.FieldByIndexErr(index)
+			if err != nil || field.IsNil() {
+				return 0, false
 			}
+			return snowflake.ID(field.Elem().Uint()), true
 		}
 	}
+	return func(reflect.Value) (snowflake.ID, bool) { return 0, false }
 }
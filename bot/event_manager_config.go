@@ -15,7 +15,10 @@ func defaultEventManagerConfig() eventManagerConfig {
 type eventManagerConfig struct {
 	Logger             *slog.Logger
 	EventListeners     []EventListener
+	Middlewares        []EventMiddleware
 	AsyncEventsEnabled bool
+	Workers            int
+	QueueSize          int
 
 	GatewayHandlers   map[gateway.EventType]GatewayEventHandler
 	HTTPServerHandler HTTPServerEventHandler
@@ -55,6 +58,13 @@ func WithListenerChan[E Event](c chan<- E) EventManagerConfigOpt {
 	return WithListeners(NewListenerChan(c))
 }
 
+// WithEventMiddlewares adds the given EventMiddleware(s) to the eventManagerConfig.
+func WithEventMiddlewares(middlewares ...EventMiddleware) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.Middlewares = append(config.Middlewares, middlewares...)
+	}
+}
+
 // WithAsyncEventsEnabled enables/disables the async events.
 func WithAsyncEventsEnabled() EventManagerConfigOpt {
 	return func(config *eventManagerConfig) {
@@ -62,6 +72,20 @@ func WithAsyncEventsEnabled() EventManagerConfigOpt {
 	}
 }
 
+// WithEventWorkerPool dispatches events to a fixed number of workers, each with a queue of the given size.
+// Events of the same guild are always handled by the same worker, so their order is kept.
+// Events which don't belong to a guild are ordered by their shard.
+// If the queue of a worker is full, DispatchEvent blocks until there is space again.
+// Events dispatched by listeners never block, they are kept in an unbounded overflow of the worker while its queue is full.
+// Unlike the default EventManager, which calls listeners for one event at a time, listeners are called concurrently for events of different workers and must be safe for concurrent use.
+// This overrides WithAsyncEventsEnabled.
+func WithEventWorkerPool(workers int, queueSize int) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.Workers = workers
+		config.QueueSize = queueSize
+	}
+}
+
 // WithGatewayHandlers overrides the default GatewayEventHandler(s) in the eventManagerConfig.
 func WithGatewayHandlers(handlers map[gateway.EventType]GatewayEventHandler) EventManagerConfigOpt {
 	return func(config *eventManagerConfig) {
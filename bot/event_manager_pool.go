@@ -0,0 +1,338 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ WorkerPoolEventManager = (*workerPoolEventManager)(nil)
+
+// WorkerPoolEventManager is an EventManager which dispatches events to a bounded pool of workers.
+// It is returned by NewEventManager when WithEventWorkerPool is used.
+type WorkerPoolEventManager interface {
+	EventManager
+
+	// Metrics returns a snapshot of the current EventWorkerPoolMetrics.
+	Metrics() EventWorkerPoolMetrics
+
+	// Close stops accepting new events and waits until all queued events are handled or the context is done.
+	Close(ctx context.Context)
+}
+
+// EventWorkerPoolMetrics contains metrics about the workers and queues of a WorkerPoolEventManager.
+type EventWorkerPoolMetrics struct {
+	// Workers is the number of workers.
+	Workers int
+	// QueueSize is the size of the queue of each worker.
+	QueueSize int
+	// Queued is the number of events currently waiting in all queues.
+	Queued int
+	// MaxQueued is the number of events waiting in the fullest queue.
+	MaxQueued int
+	// Listeners is the number of registered EventListener(s).
+	Listeners int
+	// Dispatched is the total number of events passed to DispatchEvent.
+	Dispatched uint64
+	// Handled is the total number of events all listeners have been called for.
+	Handled uint64
+	// Dropped is the total number of events dispatched after the EventManager was closed.
+	Dropped uint64
+	// Blocked is the total number of times DispatchEvent had to wait because a queue was full.
+	Blocked uint64
+	// BlockedDuration is the total time DispatchEvent spent waiting for full queues.
+	BlockedDuration time.Duration
+	// Overflowed is the total number of events which were put into the overflow of a worker instead of its full queue, because they were dispatched by a listener.
+	Overflowed uint64
+}
+
+func newWorkerPoolEventManager(client *Client, cfg eventManagerConfig) *workerPoolEventManager {
+	m := &workerPoolEventManager{
+		eventManagerImpl: &eventManagerImpl{
+			client:            client,
+			logger:            cfg.Logger,
+			eventListeners:    newListenerRegistry(cfg.EventListeners),
+			gatewayHandlers:   cfg.GatewayHandlers,
+			httpServerHandler: cfg.HTTPServerHandler,
+		},
+		queueSize: max(cfg.QueueSize, 0),
+		workers:   make([]*eventWorker, cfg.Workers),
+		done:      make(chan struct{}),
+	}
+	m.listenersHandler = m.callListeners
+	m.Use(cfg.Middlewares...)
+	for i := range m.workers {
+		m.workers[i] = &eventWorker{
+			queue: make(chan Event, m.queueSize),
+			wake:  make(chan struct{}, 1),
+		}
+		m.wg.Add(1)
+		go m.work(m.workers[i])
+	}
+	return m
+}
+
+type workerPoolEventManager struct {
+	*eventManagerImpl
+
+	queueSize int
+	workers   []*eventWorker
+	wg        sync.WaitGroup
+
+	closeMu sync.RWMutex
+	closed  bool
+	done    chan struct{}
+	// sending tracks DispatchEvent calls which wait for space in a full queue
+	sending sync.WaitGroup
+
+	dispatched      atomic.Uint64
+	handled         atomic.Uint64
+	dropped         atomic.Uint64
+	blocked         atomic.Uint64
+	blockedDuration atomic.Int64
+	overflowed      atomic.Uint64
+}
+
+// eventWorker handles the events of its queue in order.
+// Events dispatched by listeners while the queue is full are put into the overflow instead, so workers never wait for each other or themselves.
+type eventWorker struct {
+	queue chan Event
+	wake  chan struct{}
+	// goroutineID is the id of the goroutine running this worker
+	goroutineID atomic.Uint64
+
+	mu       sync.Mutex
+	overflow []Event
+}
+
+// DispatchEvent queues the event on the worker of its guild or shard and blocks while that queue is full.
+// Listeners running on a worker never block, their events are put into the overflow of the worker instead.
+func (m *workerPoolEventManager) DispatchEvent(event Event) {
+	m.dispatched.Add(1)
+
+	m.closeMu.RLock()
+	if m.closed {
+		m.closeMu.RUnlock()
+		m.drop(event)
+		return
+	}
+
+	w := m.workers[workerIndex(eventOrderingKey(event), len(m.workers))]
+	w.mu.Lock()
+	// keep the order, once events are in the overflow all following events go there too until the worker took them
+	if len(w.overflow) > 0 {
+		m.addOverflow(w, event)
+		w.mu.Unlock()
+		m.closeMu.RUnlock()
+		return
+	}
+	select {
+	case w.queue <- event:
+		w.mu.Unlock()
+		m.closeMu.RUnlock()
+		return
+	default:
+	}
+	if m.isWorker() {
+		m.addOverflow(w, event)
+		w.mu.Unlock()
+		m.closeMu.RUnlock()
+		return
+	}
+	w.mu.Unlock()
+
+	// wait without holding the lock, so Close is not blocked by full queues
+	m.sending.Add(1)
+	m.closeMu.RUnlock()
+	defer m.sending.Done()
+
+	m.blocked.Add(1)
+	start := time.Now()
+	select {
+	case w.queue <- event:
+		m.blockedDuration.Add(int64(time.Since(start)))
+	case <-m.done:
+		m.drop(event)
+	}
+}
+
+func (m *workerPoolEventManager) drop(event Event) {
+	m.dropped.Add(1)
+	m.logger.Warn("dropping event dispatched after event manager was closed", slog.String("type", reflect.TypeOf(event).String()))
+}
+
+// addOverflow adds the event to the overflow of the worker and wakes it up. The mutex of the worker must be held.
+func (m *workerPoolEventManager) addOverflow(w *eventWorker, event Event) {
+	m.overflowed.Add(1)
+	w.overflow = append(w.overflow, event)
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// isWorker returns whether the current goroutine is one of the workers.
+func (m *workerPoolEventManager) isWorker() bool {
+	id := goroutineID()
+	for _, w := range m.workers {
+		if w.goroutineID.Load() == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *workerPoolEventManager) Metrics() EventWorkerPoolMetrics {
+	metrics := EventWorkerPoolMetrics{
+		Workers:         len(m.workers),
+		QueueSize:       m.queueSize,
+		Listeners:       m.eventListeners.len(),
+		Dispatched:      m.dispatched.Load(),
+		Handled:         m.handled.Load(),
+		Dropped:         m.dropped.Load(),
+		Blocked:         m.blocked.Load(),
+		BlockedDuration: time.Duration(m.blockedDuration.Load()),
+		Overflowed:      m.overflowed.Load(),
+	}
+	for _, w := range m.workers {
+		w.mu.Lock()
+		queued := len(w.queue) + len(w.overflow)
+		w.mu.Unlock()
+		metrics.Queued += queued
+		metrics.MaxQueued = max(metrics.MaxQueued, queued)
+	}
+	return metrics
+}
+
+func (m *workerPoolEventManager) Close(ctx context.Context) {
+	m.closeMu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+	m.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		m.logger.Warn("event manager closed before all queued events were handled", slog.Int("queued", m.Metrics().Queued))
+	case <-done:
+	}
+}
+
+func (m *workerPoolEventManager) work(w *eventWorker) {
+	defer m.wg.Done()
+	w.goroutineID.Store(goroutineID())
+	for {
+		// queued events are older than the ones in the overflow
+		select {
+		case event := <-w.queue:
+			m.handle(event)
+			continue
+		default:
+		}
+		if m.handleOverflow(w) {
+			continue
+		}
+
+		select {
+		case event := <-w.queue:
+			m.handle(event)
+		case <-w.wake:
+		case <-m.done:
+			// handle the remaining events once all waiting DispatchEvent calls have finished
+			m.sending.Wait()
+			for {
+				select {
+				case event := <-w.queue:
+					m.handle(event)
+					continue
+				default:
+				}
+				if !m.handleOverflow(w) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// handleOverflow handles all events in the overflow of the worker and returns false if there were none.
+func (m *workerPoolEventManager) handleOverflow(w *eventWorker) bool {
+	w.mu.Lock()
+	overflow := w.overflow
+	w.overflow = nil
+	w.mu.Unlock()
+	for _, event := range overflow {
+		m.handle(event)
+	}
+	return len(overflow) > 0
+}
+
+func (m *workerPoolEventManager) handle(event Event) {
+	defer m.handled.Add(1)
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("recovered from panic in event middleware", slog.Any("arg", r), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+	if err := (*m.handler.Load())(event); err != nil {
+		m.logger.Error("error while handling event", slog.String("type", reflect.TypeOf(event).String()), slog.Any("err", err))
+	}
+}
+
+func (m *workerPoolEventManager) callListeners(event Event) error {
+	for _, listener := range m.eventListeners.listenersFor(event) {
+		m.callListener(listener, event)
+	}
+	return nil
+}
+
+func (m *workerPoolEventManager) callListener(listener EventListener, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+	listener.OnEvent(event)
+}
+
+// eventOrderingKey returns the guild id of the event or the shard id if the event does not belong to a guild.
+func eventOrderingKey(event Event) uint64 {
+	if guildID, ok := EventGuildID(event); ok {
+		return uint64(guildID)
+	}
+	if e, ok := event.(interface{ ShardID() int }); ok {
+		return uint64(e.ShardID())
+	}
+	return 0
+}
+
+func workerIndex(key uint64, workers int) int {
+	// spread snowflakes evenly as their lower bits are mostly the same
+	return int((key * 0x9E3779B97F4A7C15 >> 32) % uint64(workers))
+}
+
+// goroutineID returns the id of the current goroutine, which is the number after "goroutine " in its stack trace.
+// It is only used to detect listeners dispatching events while a queue is full.
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	stack, _ = bytes.CutPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i >= 0 {
+		stack = stack[:i]
+	}
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+	return id
+}
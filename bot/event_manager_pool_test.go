@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type testEvent struct {
+	GuildID  snowflake.ID
+	Sequence int
+}
+
+func (e *testEvent) Client() *Client       { return nil }
+func (e *testEvent) SequenceNumber() int   { return e.Sequence }
+func (e *testEvent) Cancel()               {}
+func (e *testEvent) CancelCause(err error) {}
+func (e *testEvent) IsCanceled() bool      { return false }
+func (e *testEvent) ShardID() int          { return 0 }
+
+type testPointerEvent struct {
+	GuildID *snowflake.ID
+}
+
+func (e *testPointerEvent) Client() *Client       { return nil }
+func (e *testPointerEvent) SequenceNumber() int   { return 0 }
+func (e *testPointerEvent) Cancel()               {}
+func (e *testPointerEvent) CancelCause(err error) {}
+func (e *testPointerEvent) IsCanceled() bool      { return false }
+
+func TestEventGuildID(t *testing.T) {
+	guildID := snowflake.ID(123)
+
+	data := []struct {
+		event    Event
+		expected snowflake.ID
+		ok       bool
+	}{
+		{event: &testEvent{GuildID: 123}, expected: 123, ok: true},
+		{event: &testEvent{}, expected: 0, ok: false},
+		{event: &testPointerEvent{GuildID: &guildID}, expected: 123, ok: true},
+		{event: &testPointerEvent{}, expected: 0, ok: false},
+	}
+
+	for _, d := range data {
+		guildID, ok := EventGuildID(d.event)
+		if guildID != d.expected || ok != d.ok {
+			t.Errorf("expected %d, %t for %T, got %d, %t", d.expected, d.ok, d.event, guildID, ok)
+		}
+	}
+}
+
+func TestWorkerPoolEventManager_GuildOrdering(t *testing.T) {
+	cfg := defaultEventManagerConfig()
+	cfg.apply([]EventManagerConfigOpt{WithEventWorkerPool(4, 1)})
+
+	var (
+		mu       sync.Mutex
+		received = map[snowflake.ID][]int{}
+	)
+	cfg.EventListeners = []EventListener{NewListenerFunc(func(e *testEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[e.GuildID] = append(received[e.GuildID], e.Sequence)
+	})}
+	m := newWorkerPoolEventManager(nil, cfg)
+
+	const events = 100
+	guildIDs := []snowflake.ID{1, 2, 3, 4, 5, 6, 7, 8}
+	for i := range events {
+		for _, guildID := range guildIDs {
+			m.DispatchEvent(&testEvent{GuildID: guildID, Sequence: i})
+		}
+	}
+	m.Close(context.Background())
+
+	for _, guildID := range guildIDs {
+		sequences := received[guildID]
+		if len(sequences) != events {
+			t.Fatalf("expected %d events for guild %d, got %d", events, guildID, len(sequences))
+		}
+		for i, sequence := range sequences {
+			if sequence != i {
+				t.Fatalf("expected event %d of guild %d at position %d, got %d", i, guildID, i, sequence)
+			}
+		}
+	}
+
+	metrics := m.Metrics()
+	if metrics.Dispatched != events*uint64(len(guildIDs)) || metrics.Handled != metrics.Dispatched {
+		t.Errorf("expected all events to be dispatched and handled, got %+v", metrics)
+	}
+}
+
+func TestWorkerPoolEventManager_DropAfterClose(t *testing.T) {
+	cfg := defaultEventManagerConfig()
+	cfg.apply([]EventManagerConfigOpt{WithEventWorkerPool(1, 1)})
+	m := newWorkerPoolEventManager(nil, cfg)
+	m.Close(context.Background())
+
+	m.DispatchEvent(&testEvent{GuildID: 1})
+	if dropped := m.Metrics().Dropped; dropped != 1 {
+		t.Errorf("expected 1 dropped event, got %d", dropped)
+	}
+}
+
+func TestWorkerPoolEventManager_ReentrantDispatch(t *testing.T) {
+	cfg := defaultEventManagerConfig()
+	cfg.apply([]EventManagerConfigOpt{WithEventWorkerPool(2, 1)})
+
+	const events = 50
+	var (
+		mu       sync.Mutex
+		received []int
+		m        *workerPoolEventManager
+	)
+	cfg.EventListeners = []EventListener{NewListenerFunc(func(e *testEvent) {
+		mu.Lock()
+		received = append(received, e.Sequence)
+		mu.Unlock()
+		// dispatch all following events at once onto the full queue of this worker
+		if e.Sequence == 0 {
+			for i := 1; i < events; i++ {
+				m.DispatchEvent(&testEvent{GuildID: e.GuildID, Sequence: i})
+			}
+		}
+	})}
+	m = newWorkerPoolEventManager(nil, cfg)
+
+	m.DispatchEvent(&testEvent{GuildID: 1, Sequence: 0})
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Metrics().Handled < events {
+		if time.Now().After(deadline) {
+			t.Fatal("worker deadlocked on re-entrant dispatch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	m.Close(context.Background())
+
+	if len(received) != events {
+		t.Fatalf("expected %d events, got %d", events, len(received))
+	}
+	for i, sequence := range received {
+		if sequence != i {
+			t.Fatalf("expected event %d at position %d, got %d", i, i, sequence)
+		}
+	}
+	if m.Metrics().Overflowed == 0 {
+		t.Error("expected events to overflow")
+	}
+}
+
+func TestWorkerPoolEventManager_CloseWhileBlocked(t *testing.T) {
+	cfg := defaultEventManagerConfig()
+	cfg.apply([]EventManagerConfigOpt{WithEventWorkerPool(1, 1)})
+
+	release := make(chan struct{})
+	cfg.EventListeners = []EventListener{NewListenerFunc(func(e *testEvent) {
+		<-release
+	})}
+	m := newWorkerPoolEventManager(nil, cfg)
+
+	// the first event blocks the worker, the second fills the queue and the third waits for space
+	m.DispatchEvent(&testEvent{GuildID: 1})
+	m.DispatchEvent(&testEvent{GuildID: 1})
+	dispatched := make(chan struct{})
+	go func() {
+		defer close(dispatched)
+		m.DispatchEvent(&testEvent{GuildID: 1})
+	}()
+	for m.Metrics().Blocked == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		m.Close(ctx)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return when its context was done")
+	}
+
+	close(release)
+	<-dispatched
+	m.Close(context.Background())
+	if metrics := m.Metrics(); metrics.Handled+metrics.Dropped != metrics.Dispatched {
+		t.Errorf("expected all events to be handled or dropped, got %+v", metrics)
+	}
+}
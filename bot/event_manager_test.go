@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEventManager_Use(t *testing.T) {
+	var calls []string
+	record := func(name string) EventMiddleware {
+		return func(next EventHandlerFunc) EventHandlerFunc {
+			return func(event Event) error {
+				calls = append(calls, name)
+				return next(event)
+			}
+		}
+	}
+
+	m := NewEventManager(&Client{},
+		WithEventMiddlewares(record("config")),
+		WithListenerFunc(func(e *testEvent) {
+			calls = append(calls, "listener")
+		}),
+	)
+	m.DispatchEvent(&testEvent{})
+	m.Use(record("use"))
+	m.DispatchEvent(&testEvent{})
+
+	expected := []string{"config", "listener", "config", "use", "listener"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestRecoverEventMiddleware(t *testing.T) {
+	var err error
+	m := NewEventManager(&Client{},
+		WithEventMiddlewares(func(next EventHandlerFunc) EventHandlerFunc {
+			return func(event Event) error {
+				err = next(event)
+				return err
+			}
+		}, RecoverEventMiddleware),
+		WithListenerFunc(func(e *testEvent) {
+			panic("test")
+		}),
+	)
+	m.DispatchEvent(&testEvent{})
+
+	var panicErr *EventPanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "test" {
+		t.Errorf("expected *EventPanicError with value test, got %v", err)
+	}
+}
+
+func TestEventManager_ShutdownTracksSyncListeners(t *testing.T) {
+	client := &Client{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	client.EventManager = NewEventManager(client, WithListenerFunc(func(e *testEvent) {
+		close(started)
+		<-release
+	}))
+	go client.EventManager.DispatchEvent(&testEvent{})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	report := client.Shutdown(ctx)
+	if report.AbandonedWork[WorkKindEventListener] != 1 {
+		t.Errorf("expected 1 abandoned event listener, got %v", report.AbandonedWork)
+	}
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type (
+	// EventHandlerFunc handles an Event and returns an error if handling it failed.
+	EventHandlerFunc func(event Event) error
+
+	// EventMiddleware wraps an EventHandlerFunc to intercept and short-circuit events before they reach the EventListener(s).
+	// Middlewares are executed in the order they were added with WithEventMiddlewares or EventManager.Use.
+	EventMiddleware func(next EventHandlerFunc) EventHandlerFunc
+)
+
+// chainEventMiddlewares wraps the given EventHandlerFunc with the given EventMiddleware(s).
+func chainEventMiddlewares(middlewares []EventMiddleware, handler EventHandlerFunc) EventHandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// EventPanicError is returned by RecoverEventMiddleware when the next EventHandlerFunc panicked.
+type EventPanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *EventPanicError) Error() string {
+	return fmt.Sprintf("recovered from panic while handling event: %v", e.Value)
+}
+
+// RecoverEventMiddleware is an EventMiddleware which converts panics of the next EventHandlerFunc into an *EventPanicError.
+//
+// It only catches panics of EventListener(s) which are called synchronously by the next EventHandlerFunc.
+// With WithAsyncEventsEnabled every EventListener runs in its own goroutine and with WithEventWorkerPool every EventListener is recovered on its own,
+// in both cases panics of EventListener(s) are logged by the EventManager and never reach this middleware.
+var RecoverEventMiddleware EventMiddleware = func(next EventHandlerFunc) EventHandlerFunc {
+	return func(event Event) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &EventPanicError{
+					Value: r,
+					Stack: debug.Stack(),
+				}
+			}
+		}()
+		return next(event)
+	}
+}
+
+// LoggerEventMiddleware is an EventMiddleware which logs every event with the given level and how long it took to handle it.
+func LoggerEventMiddleware(logger *slog.Logger, level slog.Level) EventMiddleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(event Event) error {
+			start := time.Now()
+			err := next(event)
+
+			attrs := []slog.Attr{
+				slog.String("type", reflect.TypeOf(event).String()),
+				slog.Int("sequence", event.SequenceNumber()),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if guildID, ok := EventGuildID(event); ok {
+				attrs = append(attrs, slog.Any("guild_id", guildID))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("err", err))
+			}
+			logger.LogAttrs(context.Background(), level, "handled event", attrs...)
+			return err
+		}
+	}
+}
+
+// GuildFilterEventMiddleware is an EventMiddleware which drops all events of guilds not passing the given filter.
+// Events which don't belong to a guild are always passed on.
+func GuildFilterEventMiddleware(filter func(guildID snowflake.ID) bool) EventMiddleware {
+	return func(next EventHandlerFunc) EventHandlerFunc {
+		return func(event Event) error {
+			if guildID, ok := EventGuildID(event); ok && !filter(guildID) {
+				return nil
+			}
+			return next(event)
+		}
+	}
+}
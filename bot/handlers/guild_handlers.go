@@ -36,9 +36,12 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 		client.Caches.AddMember(member)
 	}
 
-	for _, voiceState := range event.VoiceStates {
-		voiceState.GuildID = event.ID // populate unset field
-		client.Caches.AddVoiceState(voiceState)
+	for i := range event.VoiceStates {
+		event.VoiceStates[i].GuildID = event.ID // populate unset field
+		client.Caches.AddVoiceState(event.VoiceStates[i])
+	}
+	if client.VoiceTracker != nil {
+		client.VoiceTracker.LoadGuild(event.ID, event.VoiceStates)
 	}
 
 	for _, emoji := range event.Emojis {
@@ -63,9 +66,12 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 		client.Caches.AddGuildSoundboardSound(soundboardSound)
 	}
 
-	for _, presence := range event.Presences {
-		presence.GuildID = event.ID // populate unset field
-		client.Caches.AddPresence(presence)
+	for i := range event.Presences {
+		event.Presences[i].GuildID = event.ID // populate unset field
+		client.Caches.AddPresence(event.Presences[i])
+	}
+	if client.PresenceAggregator != nil {
+		client.PresenceAggregator.LoadGuild(event.ID, event.Presences)
 	}
 
 	genericGuildEvent := &events.GenericGuild{
@@ -84,7 +90,10 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
 			})
 		}
-		if client.MemberChunkingManager.MemberChunkingFilter()(event.ID) {
+		// the MemberBackfiller paces the member requests itself, so the guild is only chunked directly without it
+		if client.MemberBackfiller != nil {
+			client.MemberBackfiller.Enqueue(shardID, event.ID)
+		} else if client.MemberChunkingManager.MemberChunkingFilter()(event.ID) {
 			go func() {
 				if _, err := client.MemberChunkingManager.RequestMembersWithQuery(context.Background(), event.ID, "", 0); err != nil {
 					client.Logger.Error("failed to chunk guild on guild_create", slog.Any("err", err))
@@ -106,6 +115,9 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 			Guild:        event.GatewayGuild,
 		})
 	}
+	if client.MemberBackfiller != nil {
+		client.MemberBackfiller.Enqueue(shardID, event.ID)
+	}
 }
 
 func gatewayHandlerGuildUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventGuildUpdate) {
@@ -130,6 +142,12 @@ func gatewayHandlerGuildDelete(client *bot.Client, sequenceNumber int, shardID i
 	guild, _ := client.Caches.RemoveGuild(event.ID)
 	client.Caches.RemoveVoiceStatesByGuildID(event.ID)
 	client.Caches.RemovePresencesByGuildID(event.ID)
+	if client.PresenceAggregator != nil {
+		client.PresenceAggregator.RemoveGuild(event.ID)
+	}
+	if client.VoiceTracker != nil {
+		client.VoiceTracker.RemoveGuild(event.ID)
+	}
 	// TODO: figure out a better way to remove thread members from cache via guild id without requiring cached GuildThreads
 	for channel := range client.Caches.Channels() {
 		if guildThread, ok := channel.(discord.GuildThread); ok && guildThread.GuildID() == event.ID {
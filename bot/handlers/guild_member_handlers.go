@@ -44,6 +44,9 @@ func gatewayHandlerGuildMemberRemove(client *bot.Client, sequenceNumber int, sha
 	}
 
 	member, _ := client.Caches.RemoveMember(event.GuildID, event.User.ID)
+	if client.PresenceAggregator != nil {
+		client.PresenceAggregator.RemoveMember(event.GuildID, event.User.ID)
+	}
 
 	client.EventManager.DispatchEvent(&events.GuildMemberLeave{
 		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
@@ -4,6 +4,7 @@ import (
 	"github.com/disgoorg/snowflake/v2"
 
 	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/gateway"
@@ -67,6 +68,7 @@ func gatewayHandlerMessageCreate(client *bot.Client, sequenceNumber int, shardID
 func gatewayHandlerMessageUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventMessageUpdate) {
 	oldMessage, _ := client.Caches.Message(event.ChannelID, event.ID)
 	client.Caches.AddMessage(event.Message)
+	history, _ := client.Caches.MessageHistory(event.ChannelID, event.ID)
 
 	genericEvent := events.NewGenericEvent(client, sequenceNumber, shardID)
 	client.EventManager.DispatchEvent(&events.MessageUpdate{
@@ -78,6 +80,7 @@ func gatewayHandlerMessageUpdate(client *bot.Client, sequenceNumber int, shardID
 			GuildID:      event.GuildID,
 		},
 		OldMessage: oldMessage,
+		History:    history,
 	})
 
 	if event.GuildID == nil {
@@ -89,6 +92,7 @@ func gatewayHandlerMessageUpdate(client *bot.Client, sequenceNumber int, shardID
 				ChannelID:    event.ChannelID,
 			},
 			OldMessage: oldMessage,
+			History:    history,
 		})
 	} else {
 		client.EventManager.DispatchEvent(&events.GuildMessageUpdate{
@@ -100,6 +104,7 @@ func gatewayHandlerMessageUpdate(client *bot.Client, sequenceNumber int, shardID
 				GuildID:      *event.GuildID,
 			},
 			OldMessage: oldMessage,
+			History:    history,
 		})
 	}
 }
@@ -109,15 +114,36 @@ func gatewayHandlerMessageDelete(client *bot.Client, sequenceNumber int, shardID
 }
 
 func gatewayHandlerMessageDeleteBulk(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventMessageDeleteBulk) {
+	messages := make(map[snowflake.ID]discord.Message)
+	histories := make(map[snowflake.ID]cache.MessageHistory)
 	for _, messageID := range event.IDs {
-		handleMessageDelete(client, sequenceNumber, shardID, messageID, event.ChannelID, event.GuildID)
+		message, history, ok := handleMessageDelete(client, sequenceNumber, shardID, messageID, event.ChannelID, event.GuildID)
+		if ok {
+			messages[messageID] = message
+		}
+		if history.Original.ID != 0 {
+			histories[messageID] = history
+		}
 	}
+
+	if event.GuildID == nil {
+		return
+	}
+	client.EventManager.DispatchEvent(&events.GuildMessageDeleteBulk{
+		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+		ChannelID:    event.ChannelID,
+		GuildID:      *event.GuildID,
+		MessageIDs:   event.IDs,
+		Messages:     messages,
+		Histories:    histories,
+	})
 }
 
-func handleMessageDelete(client *bot.Client, sequenceNumber int, shardID int, messageID snowflake.ID, channelID snowflake.ID, guildID *snowflake.ID) {
+func handleMessageDelete(client *bot.Client, sequenceNumber int, shardID int, messageID snowflake.ID, channelID snowflake.ID, guildID *snowflake.ID) (discord.Message, cache.MessageHistory, bool) {
 	genericEvent := events.NewGenericEvent(client, sequenceNumber, shardID)
 
-	message, _ := client.Caches.RemoveMessage(channelID, messageID)
+	history, _ := client.Caches.MessageHistory(channelID, messageID)
+	message, ok := client.Caches.RemoveMessage(channelID, messageID)
 
 	if channel, ok := client.Caches.GuildThread(channelID); ok {
 		if channel.MessageCount > 0 {
@@ -134,6 +160,7 @@ func handleMessageDelete(client *bot.Client, sequenceNumber int, shardID int, me
 			ChannelID:    channelID,
 			GuildID:      guildID,
 		},
+		History: history,
 	})
 
 	if guildID == nil {
@@ -144,6 +171,7 @@ func handleMessageDelete(client *bot.Client, sequenceNumber int, shardID int, me
 				Message:      message,
 				ChannelID:    channelID,
 			},
+			History: history,
 		})
 	} else {
 		client.EventManager.DispatchEvent(&events.GuildMessageDelete{
@@ -154,6 +182,8 @@ func handleMessageDelete(client *bot.Client, sequenceNumber int, shardID int, me
 				ChannelID:    channelID,
 				GuildID:      *guildID,
 			},
+			History: history,
 		})
 	}
+	return message, history, ok
 }
@@ -20,6 +20,27 @@ func gatewayHandlerPresenceUpdate(client *bot.Client, sequenceNumber int, shardI
 		EventPresenceUpdate: event,
 	})
 
+	if client.PresenceAggregator != nil {
+		for _, change := range client.PresenceAggregator.Update(event.Presence) {
+			genericUserActivity := &events.GenericUserActivity{
+				GenericEvent: genericEvent,
+				UserID:       change.UserID,
+				GuildID:      change.GuildID,
+				Activity:     change.Activity,
+			}
+			switch change.Type {
+			case bot.PresenceChangeStreamingStart:
+				client.EventManager.DispatchEvent(&events.UserStreamingStart{
+					GenericUserActivity: genericUserActivity,
+				})
+			case bot.PresenceChangeStreamingStop:
+				client.EventManager.DispatchEvent(&events.UserStreamingStop{
+					GenericUserActivity: genericUserActivity,
+				})
+			}
+		}
+	}
+
 	if client.Caches.CacheFlags().Missing(cache.FlagPresences) {
 		return
 	}
@@ -38,3 +38,14 @@ func gatewayHandlerResumed(client *bot.Client, sequenceNumber int, shardID int,
 		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
 	})
 }
+
+func gatewayHandlerRateLimited(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventRateLimited) {
+	if client.MemberBackfiller != nil {
+		client.MemberBackfiller.HandleRateLimited(shardID, event)
+	}
+
+	client.EventManager.DispatchEvent(&events.GatewayRateLimited{
+		GenericEvent:     events.NewGenericEvent(client, sequenceNumber, shardID),
+		EventRateLimited: event,
+	})
+}
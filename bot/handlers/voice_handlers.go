@@ -56,6 +56,57 @@ func gatewayHandlerVoiceStateUpdate(client *bot.Client, sequenceNumber int, shar
 	} else {
 		client.Logger.Warn("could not decide which GuildVoice to fire")
 	}
+
+	if client.VoiceTracker != nil {
+		for _, change := range client.VoiceTracker.Update(event.VoiceState) {
+			genericGuildVoiceSession := &events.GenericGuildVoiceSession{
+				GenericEvent: genericGuildVoiceEvent.GenericEvent,
+				GuildID:      change.GuildID,
+				Member:       member,
+				Session:      change.Session,
+			}
+			genericGuildVoiceChannelOccupancy := &events.GenericGuildVoiceChannelOccupancy{
+				GenericEvent: genericGuildVoiceEvent.GenericEvent,
+				GuildID:      change.GuildID,
+				ChannelID:    change.ChannelID,
+				UserID:       change.UserID,
+			}
+			switch change.Type {
+			case bot.VoiceChangeJoin:
+				client.EventManager.DispatchEvent(&events.GuildVoiceSessionJoin{
+					GenericGuildVoiceSession: genericGuildVoiceSession,
+					ChannelID:                change.ChannelID,
+				})
+			case bot.VoiceChangeMove:
+				client.EventManager.DispatchEvent(&events.GuildVoiceSessionMove{
+					GenericGuildVoiceSession: genericGuildVoiceSession,
+					ChannelID:                change.ChannelID,
+					OldChannelID:             change.OldChannelID,
+					Duration:                 change.Duration,
+				})
+			case bot.VoiceChangeLeave:
+				client.EventManager.DispatchEvent(&events.GuildVoiceSessionLeave{
+					GenericGuildVoiceSession: genericGuildVoiceSession,
+					ChannelID:                change.ChannelID,
+					Duration:                 change.Duration,
+				})
+			case bot.VoiceChangeSessionEnd:
+				client.EventManager.DispatchEvent(&events.GuildVoiceSessionEnd{
+					GenericGuildVoiceSession: genericGuildVoiceSession,
+					Duration:                 change.Duration,
+				})
+			case bot.VoiceChangeChannelOccupied:
+				client.EventManager.DispatchEvent(&events.GuildVoiceChannelOccupied{
+					GenericGuildVoiceChannelOccupancy: genericGuildVoiceChannelOccupancy,
+				})
+			case bot.VoiceChangeChannelEmpty:
+				client.EventManager.DispatchEvent(&events.GuildVoiceChannelEmpty{
+					GenericGuildVoiceChannelOccupancy: genericGuildVoiceChannelOccupancy,
+					Duration:                          change.Duration,
+				})
+			}
+		}
+	}
 }
 
 func gatewayHandlerVoiceServerUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventVoiceServerUpdate) {
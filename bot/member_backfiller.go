@@ -0,0 +1,374 @@
+package bot
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+var _ MemberBackfiller = (*memberBackfillerImpl)(nil)
+
+// MemberBackfillOrder defines in which order queued guilds are backfilled.
+type MemberBackfillOrder int
+
+const (
+	// MemberBackfillOrderLargestFirst backfills guilds with the most members first.
+	MemberBackfillOrderLargestFirst MemberBackfillOrder = iota
+	// MemberBackfillOrderSmallestFirst backfills guilds with the fewest members first.
+	MemberBackfillOrderSmallestFirst
+)
+
+// MemberBackfillProgress is a snapshot of the progress of a MemberBackfiller.
+type MemberBackfillProgress struct {
+	// Queued is the number of guilds waiting to be backfilled.
+	Queued int
+	// InFlight is the number of guilds currently being backfilled.
+	InFlight int
+	// Completed is the number of guilds which have been backfilled successfully.
+	Completed int
+	// Failed is the number of guilds which could not be backfilled after all retries.
+	Failed int
+	// Members is the number of members received in total.
+	Members int
+}
+
+// NewMemberBackfiller returns a new MemberBackfiller with the MemberBackfillerConfigOpt(s) applied.
+// Guilds are requested via the Client's MemberChunkingManager, so the gateway.IntentGuildMembers is required.
+func NewMemberBackfiller(client *Client, opts ...MemberBackfillerConfigOpt) MemberBackfiller {
+	cfg := defaultMemberBackfillerConfig()
+	cfg.apply(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &memberBackfillerImpl{
+		client: client,
+		config: cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		shards: map[int]*backfillShard{},
+	}
+}
+
+// MemberBackfiller fills the member caches of many guilds in the background.
+// Each shard has its own priority queue and request budget, so a shard with a lot of guilds does not slow down other shards.
+// Guilds which fail because their shard disconnected or got rate limited are queued again and retried once the shard is ready.
+// When a MemberBackfiller is set, guilds becoming ready are only enqueued and no longer chunked directly by the MemberChunkingManager.
+type MemberBackfiller interface {
+	// Enqueue queues the given guild for backfilling if it passes the configured MemberChunkingFilter.
+	// Guilds which are already queued or of which all members are cached are ignored.
+	Enqueue(shardID int, guildID snowflake.ID)
+
+	// HandleRateLimited handles the gateway.EventRateLimited payloads from the discord Gateway.
+	HandleRateLimited(shardID int, event gateway.EventRateLimited)
+
+	// Progress returns the progress of all shards.
+	Progress() MemberBackfillProgress
+
+	// ShardProgress returns the progress of the given shard.
+	ShardProgress(shardID int) MemberBackfillProgress
+
+	// Close stops backfilling and waits for all in-flight requests to finish or the context to be done.
+	Close(ctx context.Context)
+}
+
+type backfillItem struct {
+	guildID     snowflake.ID
+	memberCount int
+	retries     int
+	rateLimited bool
+	index       int
+}
+
+type backfillQueue struct {
+	items []*backfillItem
+	order MemberBackfillOrder
+}
+
+func (q *backfillQueue) Len() int { return len(q.items) }
+
+func (q *backfillQueue) Less(i, j int) bool {
+	if q.order == MemberBackfillOrderSmallestFirst {
+		return q.items[i].memberCount < q.items[j].memberCount
+	}
+	return q.items[i].memberCount > q.items[j].memberCount
+}
+
+func (q *backfillQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *backfillQueue) Push(x any) {
+	item := x.(*backfillItem)
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+func (q *backfillQueue) Pop() any {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items[n-1] = nil
+	q.items = q.items[:n-1]
+	item.index = -1
+	return item
+}
+
+type backfillShard struct {
+	id          int
+	queue       backfillQueue
+	pending     map[snowflake.ID]struct{}
+	signal      chan struct{}
+	pausedUntil time.Time
+	lastRequest time.Time
+
+	current       *backfillItem
+	currentCancel context.CancelFunc
+
+	completed int
+	failed    int
+	members   int
+}
+
+func (s *backfillShard) notify() {
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *backfillShard) progress() MemberBackfillProgress {
+	progress := MemberBackfillProgress{
+		Queued:    s.queue.Len(),
+		Completed: s.completed,
+		Failed:    s.failed,
+		Members:   s.members,
+	}
+	if s.current != nil {
+		progress.InFlight = 1
+	}
+	return progress
+}
+
+type memberBackfillerImpl struct {
+	client *Client
+	config memberBackfillerConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	shards map[int]*backfillShard
+}
+
+func (b *memberBackfillerImpl) Enqueue(shardID int, guildID snowflake.ID) {
+	if b.ctx.Err() != nil || !b.config.MemberChunkingFilter(guildID) {
+		return
+	}
+
+	var memberCount int
+	if guild, ok := b.client.Caches.Guild(guildID); ok {
+		memberCount = guild.MemberCount
+		if memberCount > 0 && b.client.Caches.MembersLen(guildID) >= memberCount {
+			return
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	shard := b.shardLocked(shardID)
+	if _, ok := shard.pending[guildID]; ok {
+		return
+	}
+	if shard.current != nil && shard.current.guildID == guildID {
+		return
+	}
+	b.pushLocked(shard, &backfillItem{
+		guildID:     guildID,
+		memberCount: memberCount,
+	})
+}
+
+func (b *memberBackfillerImpl) HandleRateLimited(shardID int, event gateway.EventRateLimited) {
+	meta, ok := event.Meta.(gateway.RateLimitedMetadataRequestGuildMembers)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	shard, ok := b.shards[shardID]
+	if !ok {
+		return
+	}
+	shard.pausedUntil = time.Now().Add(time.Duration(event.RetryAfter * float64(time.Second)))
+	if shard.current != nil && shard.current.guildID == meta.GuildID {
+		shard.current.rateLimited = true
+		if shard.currentCancel != nil {
+			shard.currentCancel()
+		}
+	}
+	b.config.Logger.Debug("member request got rate limited", slog.Int("shard_id", shardID), slog.Any("guild_id", meta.GuildID), slog.Float64("retry_after", event.RetryAfter))
+}
+
+func (b *memberBackfillerImpl) Progress() MemberBackfillProgress {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.progressLocked()
+}
+
+func (b *memberBackfillerImpl) ShardProgress(shardID int) MemberBackfillProgress {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if shard, ok := b.shards[shardID]; ok {
+		return shard.progress()
+	}
+	return MemberBackfillProgress{}
+}
+
+func (b *memberBackfillerImpl) Close(ctx context.Context) {
+	b.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+}
+
+func (b *memberBackfillerImpl) progressLocked() MemberBackfillProgress {
+	var progress MemberBackfillProgress
+	for _, shard := range b.shards {
+		p := shard.progress()
+		progress.Queued += p.Queued
+		progress.InFlight += p.InFlight
+		progress.Completed += p.Completed
+		progress.Failed += p.Failed
+		progress.Members += p.Members
+	}
+	return progress
+}
+
+func (b *memberBackfillerImpl) shardLocked(shardID int) *backfillShard {
+	shard, ok := b.shards[shardID]
+	if ok {
+		return shard
+	}
+	shard = &backfillShard{
+		id:      shardID,
+		queue:   backfillQueue{order: b.config.Order},
+		pending: map[snowflake.ID]struct{}{},
+		signal:  make(chan struct{}, 1),
+	}
+	b.shards[shardID] = shard
+
+	b.wg.Add(1)
+	go b.run(shard)
+	return shard
+}
+
+func (b *memberBackfillerImpl) pushLocked(shard *backfillShard, item *backfillItem) {
+	item.rateLimited = false
+	heap.Push(&shard.queue, item)
+	shard.pending[item.guildID] = struct{}{}
+	shard.notify()
+}
+
+func (b *memberBackfillerImpl) run(shard *backfillShard) {
+	defer b.wg.Done()
+	for {
+		item, ctx, cancel, ok := b.next(shard)
+		if !ok {
+			return
+		}
+		b.backfill(ctx, cancel, shard, item)
+	}
+}
+
+// next waits for the next guild of the shard and returns it with the context of its request.
+// The request is set as current together with its cancel func, so HandleRateLimited can always abort it.
+func (b *memberBackfillerImpl) next(shard *backfillShard) (*backfillItem, context.Context, context.CancelFunc, bool) {
+	interval := time.Minute / time.Duration(max(b.config.RequestsPerMinute, 1))
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		wait := shard.pausedUntil.Sub(now)
+		if untilNext := shard.lastRequest.Add(interval).Sub(now); untilNext > wait {
+			wait = untilNext
+		}
+		if wait <= 0 && shard.queue.Len() > 0 {
+			item := heap.Pop(&shard.queue).(*backfillItem)
+			delete(shard.pending, item.guildID)
+			ctx, cancel := context.WithTimeout(b.ctx, b.config.RequestTimeout)
+			shard.current = item
+			shard.currentCancel = cancel
+			shard.lastRequest = now
+			b.mu.Unlock()
+			return item, ctx, cancel, true
+		}
+		b.mu.Unlock()
+
+		var timer <-chan time.Time
+		if wait > 0 {
+			timer = time.After(wait)
+		}
+		select {
+		case <-b.ctx.Done():
+			return nil, nil, nil, false
+		case <-shard.signal:
+		case <-timer:
+		}
+	}
+}
+
+func (b *memberBackfillerImpl) backfill(ctx context.Context, cancel context.CancelFunc, shard *backfillShard, item *backfillItem) {
+	members, err := b.client.MemberChunkingManager.RequestAllMembers(ctx, item.guildID)
+	cancel()
+
+	b.mu.Lock()
+	shard.current = nil
+	shard.currentCancel = nil
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		return
+	}
+
+	switch {
+	case err == nil:
+		shard.completed++
+		shard.members += len(members)
+	case item.rateLimited:
+		b.pushLocked(shard, item)
+	case errors.Is(err, discord.ErrShardNotReady), errors.Is(err, discord.ErrShardNotConnected), errors.Is(err, discord.ErrShardNotFound):
+		shard.pausedUntil = time.Now().Add(b.config.RetryDelay)
+		b.pushLocked(shard, item)
+	default:
+		item.retries++
+		if item.retries > b.config.MaxRetries {
+			shard.failed++
+			b.config.Logger.Error("failed to backfill guild members", slog.Int("shard_id", shard.id), slog.Any("guild_id", item.guildID), slog.Any("err", err))
+			break
+		}
+		b.config.Logger.Debug("retrying guild member backfill", slog.Int("shard_id", shard.id), slog.Any("guild_id", item.guildID), slog.Int("retries", item.retries), slog.Any("err", err))
+		b.pushLocked(shard, item)
+	}
+	_, requeued := shard.pending[item.guildID]
+	progress := b.progressLocked()
+	b.mu.Unlock()
+
+	if !requeued && b.config.ProgressFunc != nil {
+		b.config.ProgressFunc(progress)
+	}
+}
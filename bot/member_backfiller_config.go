@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultMemberBackfillerConfig() memberBackfillerConfig {
+	return memberBackfillerConfig{
+		Logger:               slog.Default(),
+		MemberChunkingFilter: MemberChunkingFilterAll,
+		Order:                MemberBackfillOrderLargestFirst,
+		RequestsPerMinute:    30,
+		RequestTimeout:       2 * time.Minute,
+		RetryDelay:           10 * time.Second,
+		MaxRetries:           3,
+	}
+}
+
+type memberBackfillerConfig struct {
+	Logger               *slog.Logger
+	MemberChunkingFilter MemberChunkingFilter
+	Order                MemberBackfillOrder
+	RequestsPerMinute    int
+	RequestTimeout       time.Duration
+	RetryDelay           time.Duration
+	MaxRetries           int
+	ProgressFunc         func(progress MemberBackfillProgress)
+}
+
+// MemberBackfillerConfigOpt is a functional option for configuring a MemberBackfiller.
+type MemberBackfillerConfigOpt func(config *memberBackfillerConfig)
+
+func (c *memberBackfillerConfig) apply(opts []MemberBackfillerConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_member_backfiller"))
+}
+
+// WithMemberBackfillerLogger overrides the default Logger in the memberBackfillerConfig.
+func WithMemberBackfillerLogger(logger *slog.Logger) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithMemberBackfillerFilter sets the MemberChunkingFilter which decides which guilds are backfilled.
+func WithMemberBackfillerFilter(filter MemberChunkingFilter) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.MemberChunkingFilter = filter
+	}
+}
+
+// WithMemberBackfillerOrder sets in which order queued guilds are backfilled.
+func WithMemberBackfillerOrder(order MemberBackfillOrder) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.Order = order
+	}
+}
+
+// WithMemberBackfillerRequestsPerMinute sets how many member requests each shard may send per minute.
+// This budget is shared with all other gateway commands of the shard, so it should stay well below gateway.CommandsPerMinute.
+func WithMemberBackfillerRequestsPerMinute(requestsPerMinute int) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.RequestsPerMinute = requestsPerMinute
+	}
+}
+
+// WithMemberBackfillerRequestTimeout sets how long to wait for all member chunks of a single guild.
+func WithMemberBackfillerRequestTimeout(timeout time.Duration) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.RequestTimeout = timeout
+	}
+}
+
+// WithMemberBackfillerRetryDelay sets how long a shard pauses before retrying after its gateway was not ready.
+func WithMemberBackfillerRetryDelay(delay time.Duration) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.RetryDelay = delay
+	}
+}
+
+// WithMemberBackfillerMaxRetries sets how often a guild is retried after a failed request before it is marked as failed.
+func WithMemberBackfillerMaxRetries(maxRetries int) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.MaxRetries = maxRetries
+	}
+}
+
+// WithMemberBackfillerProgressFunc sets a func which is called every time a guild finished backfilling or failed.
+func WithMemberBackfillerProgressFunc(progressFunc func(progress MemberBackfillProgress)) MemberBackfillerConfigOpt {
+	return func(config *memberBackfillerConfig) {
+		config.ProgressFunc = progressFunc
+	}
+}
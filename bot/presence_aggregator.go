@@ -0,0 +1,301 @@
+package bot
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var _ PresenceAggregator = (*presenceAggregatorImpl)(nil)
+
+// PresenceChangeType is the type of PresenceChange.
+type PresenceChangeType int
+
+const (
+	// PresenceChangeStreamingStart indicates that a member started streaming.
+	PresenceChangeStreamingStart PresenceChangeType = iota
+	// PresenceChangeStreamingStop indicates that a member stopped streaming.
+	PresenceChangeStreamingStop
+)
+
+// PresenceChange is a high level change of a member's presence detected by the PresenceAggregator.
+type PresenceChange struct {
+	Type    PresenceChangeType
+	GuildID snowflake.ID
+	UserID  snowflake.ID
+	// Activity is the activity which caused the change.
+	Activity discord.Activity
+}
+
+// ActivityKey identifies an activity by its type and name.
+type ActivityKey struct {
+	Type discord.ActivityType
+	Name string
+}
+
+// NewPresenceAggregator returns a new PresenceAggregator.
+func NewPresenceAggregator() PresenceAggregator {
+	return &presenceAggregatorImpl{
+		guilds: map[snowflake.ID]*guildPresences{},
+	}
+}
+
+// PresenceAggregator keeps live counters of the presences per guild, status and activity.
+// The counters are updated incrementally from the gateway.EventTypePresenceUpdate and gateway.EventTypeGuildCreate events, so they can be queried without scanning the cache.PresenceCache.
+// This requires the gateway.IntentGuildPresences.
+type PresenceAggregator interface {
+	// LoadGuild replaces the state of the given guild with the given presences without reporting any changes.
+	LoadGuild(guildID snowflake.ID, presences []discord.Presence)
+
+	// Update updates the counters with the given presence and returns all detected PresenceChange(s).
+	Update(presence discord.Presence) []PresenceChange
+
+	// RemoveMember removes the presence of the given member.
+	RemoveMember(guildID snowflake.ID, userID snowflake.ID)
+
+	// RemoveGuild removes all presences of the given guild.
+	RemoveGuild(guildID snowflake.ID)
+
+	// OnlineCount returns the number of members in the given guild which are not offline.
+	OnlineCount(guildID snowflake.ID) int
+
+	// StatusCount returns the number of members in the given guild with the given discord.OnlineStatus.
+	StatusCount(guildID snowflake.ID, status discord.OnlineStatus) int
+
+	// StatusCounts returns the number of members per discord.OnlineStatus in the given guild.
+	StatusCounts(guildID snowflake.ID) map[discord.OnlineStatus]int
+
+	// ActivityCount returns the number of members in the given guild with the given activity.
+	ActivityCount(guildID snowflake.ID, key ActivityKey) int
+
+	// ActivityCounts returns the number of members per activity in the given guild.
+	ActivityCounts(guildID snowflake.ID) map[ActivityKey]int
+
+	// Members returns the IDs of all members in the given guild with the given activity.
+	Members(guildID snowflake.ID, key ActivityKey) []snowflake.ID
+
+	// Streamers returns the IDs of all members in the given guild which are currently streaming.
+	Streamers(guildID snowflake.ID) []snowflake.ID
+}
+
+type memberPresence struct {
+	status     discord.OnlineStatus
+	activities []ActivityKey
+}
+
+type guildPresences struct {
+	members  map[snowflake.ID]memberPresence
+	statuses map[discord.OnlineStatus]int
+	// activities holds the members per activity
+	activities map[ActivityKey]map[snowflake.ID]struct{}
+	// streamers holds the members with a streaming activity
+	streamers map[snowflake.ID]struct{}
+}
+
+func newGuildPresences() *guildPresences {
+	return &guildPresences{
+		members:    map[snowflake.ID]memberPresence{},
+		statuses:   map[discord.OnlineStatus]int{},
+		activities: map[ActivityKey]map[snowflake.ID]struct{}{},
+		streamers:  map[snowflake.ID]struct{}{},
+	}
+}
+
+func (g *guildPresences) remove(userID snowflake.ID) (memberPresence, bool) {
+	old, ok := g.members[userID]
+	if !ok {
+		return memberPresence{}, false
+	}
+	delete(g.members, userID)
+	if g.statuses[old.status]--; g.statuses[old.status] <= 0 {
+		delete(g.statuses, old.status)
+	}
+	for _, key := range old.activities {
+		delete(g.activities[key], userID)
+		if len(g.activities[key]) == 0 {
+			delete(g.activities, key)
+		}
+	}
+	delete(g.streamers, userID)
+	return old, true
+}
+
+func (g *guildPresences) add(userID snowflake.ID, presence memberPresence) {
+	if presence.status == discord.OnlineStatusOffline || presence.status == "" {
+		return
+	}
+	g.members[userID] = presence
+	g.statuses[presence.status]++
+	for _, key := range presence.activities {
+		if g.activities[key] == nil {
+			g.activities[key] = map[snowflake.ID]struct{}{}
+		}
+		g.activities[key][userID] = struct{}{}
+	}
+	if slices.ContainsFunc(presence.activities, isStreaming) {
+		g.streamers[userID] = struct{}{}
+	}
+}
+
+type presenceAggregatorImpl struct {
+	mu     sync.RWMutex
+	guilds map[snowflake.ID]*guildPresences
+}
+
+func (a *presenceAggregatorImpl) LoadGuild(guildID snowflake.ID, presences []discord.Presence) {
+	guild := newGuildPresences()
+	for _, presence := range presences {
+		guild.add(presence.PresenceUser.ID, toMemberPresence(presence))
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.guilds[guildID] = guild
+}
+
+func (a *presenceAggregatorImpl) Update(presence discord.Presence) []PresenceChange {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	guild, ok := a.guilds[presence.GuildID]
+	if !ok {
+		guild = newGuildPresences()
+		a.guilds[presence.GuildID] = guild
+	}
+
+	old, _ := guild.remove(presence.PresenceUser.ID)
+	guild.add(presence.PresenceUser.ID, toMemberPresence(presence))
+
+	wasStreaming := slices.ContainsFunc(old.activities, isStreaming)
+	var changes []PresenceChange
+	var streaming bool
+	for _, activity := range presence.Activities {
+		if activity.Type != discord.ActivityTypeStreaming || presence.Status == discord.OnlineStatusOffline {
+			continue
+		}
+		streaming = true
+		if !wasStreaming {
+			changes = append(changes, PresenceChange{
+				Type:     PresenceChangeStreamingStart,
+				GuildID:  presence.GuildID,
+				UserID:   presence.PresenceUser.ID,
+				Activity: activity,
+			})
+		}
+		break
+	}
+	if wasStreaming && !streaming {
+		index := slices.IndexFunc(old.activities, isStreaming)
+		changes = append(changes, PresenceChange{
+			Type:    PresenceChangeStreamingStop,
+			GuildID: presence.GuildID,
+			UserID:  presence.PresenceUser.ID,
+			Activity: discord.Activity{
+				Type: discord.ActivityTypeStreaming,
+				Name: old.activities[index].Name,
+			},
+		})
+	}
+	return changes
+}
+
+func (a *presenceAggregatorImpl) RemoveMember(guildID snowflake.ID, userID snowflake.ID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		guild.remove(userID)
+	}
+}
+
+func (a *presenceAggregatorImpl) RemoveGuild(guildID snowflake.ID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.guilds, guildID)
+}
+
+func (a *presenceAggregatorImpl) OnlineCount(guildID snowflake.ID) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return len(guild.members)
+	}
+	return 0
+}
+
+func (a *presenceAggregatorImpl) StatusCount(guildID snowflake.ID, status discord.OnlineStatus) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return guild.statuses[status]
+	}
+	return 0
+}
+
+func (a *presenceAggregatorImpl) StatusCounts(guildID snowflake.ID) map[discord.OnlineStatus]int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return maps.Clone(guild.statuses)
+	}
+	return map[discord.OnlineStatus]int{}
+}
+
+func (a *presenceAggregatorImpl) ActivityCount(guildID snowflake.ID, key ActivityKey) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return len(guild.activities[key])
+	}
+	return 0
+}
+
+func (a *presenceAggregatorImpl) ActivityCounts(guildID snowflake.ID) map[ActivityKey]int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	counts := map[ActivityKey]int{}
+	if guild, ok := a.guilds[guildID]; ok {
+		for key, members := range guild.activities {
+			counts[key] = len(members)
+		}
+	}
+	return counts
+}
+
+func (a *presenceAggregatorImpl) Members(guildID snowflake.ID, key ActivityKey) []snowflake.ID {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return slices.Collect(maps.Keys(guild.activities[key]))
+	}
+	return nil
+}
+
+func (a *presenceAggregatorImpl) Streamers(guildID snowflake.ID) []snowflake.ID {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if guild, ok := a.guilds[guildID]; ok {
+		return slices.Collect(maps.Keys(guild.streamers))
+	}
+	return nil
+}
+
+func toMemberPresence(presence discord.Presence) memberPresence {
+	activities := make([]ActivityKey, 0, len(presence.Activities))
+	for _, activity := range presence.Activities {
+		key := ActivityKey{Type: activity.Type, Name: activity.Name}
+		if !slices.Contains(activities, key) {
+			activities = append(activities, key)
+		}
+	}
+	return memberPresence{
+		status:     presence.Status,
+		activities: activities,
+	}
+}
+
+func isStreaming(key ActivityKey) bool {
+	return key.Type == discord.ActivityTypeStreaming
+}
@@ -0,0 +1,427 @@
+package bot
+
+import (
+	"container/heap"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var _ Scheduler = (*schedulerImpl)(nil)
+
+var (
+	// ErrSchedulerClosed is returned when a task is scheduled after the Scheduler was closed.
+	ErrSchedulerClosed = errors.New("scheduler closed")
+	// ErrSchedulerAlreadyStarted is returned when Scheduler.Start is called more than once.
+	ErrSchedulerAlreadyStarted = errors.New("scheduler already started")
+	// ErrScheduledTaskNotFound is returned when a task to cancel does not exist.
+	ErrScheduledTaskNotFound = errors.New("scheduled task not found")
+	// ErrNoScheduledTaskHandler is returned when no ScheduledTaskHandler is registered for the name of a task.
+	ErrNoScheduledTaskHandler = errors.New("no scheduled task handler registered")
+)
+
+// ScheduledTask is a task which runs once at a specific time.
+type ScheduledTask struct {
+	// ID is the unique ID of the task.
+	ID string `json:"id"`
+	// Name is the name of the ScheduledTaskHandler which runs the task.
+	Name string `json:"name"`
+	// RunAt is the time the task runs at.
+	RunAt time.Time `json:"run_at"`
+	// Data is the JSON encoded data of the task.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Attempts is the number of times the task already failed.
+	Attempts int `json:"attempts"`
+}
+
+// UnmarshalData decodes the Data of the task into v.
+func (t ScheduledTask) UnmarshalData(v any) error {
+	return json.Unmarshal(t.Data, v)
+}
+
+// ScheduledTaskHandler runs a ScheduledTask. If it returns an error, the task is retried later.
+type ScheduledTaskHandler func(ctx context.Context, client *Client, task ScheduledTask) error
+
+// NewScheduler returns a new Scheduler with the SchedulerConfigOpt(s) applied.
+func NewScheduler(client *Client, opts ...SchedulerConfigOpt) Scheduler {
+	cfg := defaultSchedulerConfig()
+	cfg.apply(opts)
+
+	handlers := make(map[string]ScheduledTaskHandler, len(cfg.Handlers))
+	for name, handler := range cfg.Handlers {
+		handlers[name] = handler
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runCtx, runCancel := context.WithCancel(context.Background())
+	return &schedulerImpl{
+		client:    client,
+		config:    cfg,
+		handlers:  handlers,
+		tasks:     map[string]*scheduledItem{},
+		running:   map[string]bool{},
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+		runCtx:    runCtx,
+		runCancel: runCancel,
+	}
+}
+
+// Scheduler runs ScheduledTask(s) like "unban in 7 days" or "remind me in 2 hours" at their scheduled time.
+// Tasks are persisted in a ScheduledTaskStore and only deleted after their ScheduledTaskHandler succeeded, so every task runs at least once even if the bot restarts.
+// Handlers should therefore be idempotent.
+type Scheduler interface {
+	// Handle registers the ScheduledTaskHandler for tasks with the given name.
+	Handle(name string, handler ScheduledTaskHandler)
+
+	// Start loads all tasks from the ScheduledTaskStore and starts running them. Overdue tasks run immediately.
+	// Register all ScheduledTaskHandler(s) before calling Start.
+	Start(ctx context.Context) error
+
+	// Schedule schedules a task for the ScheduledTaskHandler with the given name at the given time.
+	// The data is encoded as JSON and can be decoded with ScheduledTask.UnmarshalData.
+	Schedule(ctx context.Context, name string, runAt time.Time, data any) (ScheduledTask, error)
+
+	// ScheduleIn schedules a task for the ScheduledTaskHandler with the given name after the given delay.
+	ScheduleIn(ctx context.Context, name string, delay time.Duration, data any) (ScheduledTask, error)
+
+	// Cancel removes the task with the given ID. Tasks which are already running are not interrupted but won't be retried.
+	Cancel(ctx context.Context, id string) error
+
+	// Tasks returns all pending tasks ordered by their RunAt time.
+	Tasks() []ScheduledTask
+
+	// Close stops running new tasks and waits for all running tasks to finish or the context to be done.
+	// Once the context is done, the contexts of the running tasks are cancelled and Close returns without waiting for them.
+	// Tasks interrupted by Close stay in the ScheduledTaskStore and run again after the next Start.
+	Close(ctx context.Context)
+}
+
+type scheduledItem struct {
+	task  ScheduledTask
+	index int
+}
+
+type scheduledQueue []*scheduledItem
+
+func (q scheduledQueue) Len() int {
+	return len(q)
+}
+
+func (q scheduledQueue) Less(i, j int) bool {
+	return q[i].task.RunAt.Before(q[j].task.RunAt)
+}
+
+func (q scheduledQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *scheduledQueue) Push(x any) {
+	item := x.(*scheduledItem)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *scheduledQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*q = old[:n-1]
+	return item
+}
+
+type schedulerImpl struct {
+	client *Client
+	config schedulerConfig
+
+	mu       sync.Mutex
+	handlers map[string]ScheduledTaskHandler
+	queue    scheduledQueue
+	tasks    map[string]*scheduledItem
+	running  map[string]bool
+	started  bool
+	closed   bool
+
+	wake      chan struct{}
+	wg        sync.WaitGroup
+	ctx       context.Context
+	cancel    context.CancelFunc
+	runCtx    context.Context
+	runCancel context.CancelFunc
+}
+
+func (s *schedulerImpl) Handle(name string, handler ScheduledTaskHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = handler
+}
+
+func (s *schedulerImpl) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrSchedulerClosed
+	}
+	if s.started {
+		s.mu.Unlock()
+		return ErrSchedulerAlreadyStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	tasks, err := s.config.Store.All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load scheduled tasks: %w", err)
+	}
+
+	s.mu.Lock()
+	for _, task := range tasks {
+		s.push(task)
+	}
+	s.mu.Unlock()
+	s.config.Logger.Debug("loaded scheduled tasks", slog.Int("tasks", len(tasks)))
+
+	s.wg.Add(1)
+	go s.loop()
+	return nil
+}
+
+func (s *schedulerImpl) Schedule(ctx context.Context, name string, runAt time.Time, data any) (ScheduledTask, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return ScheduledTask{}, fmt.Errorf("failed to marshal scheduled task data: %w", err)
+	}
+	task := ScheduledTask{
+		ID:    newScheduledTaskID(),
+		Name:  name,
+		RunAt: runAt,
+		Data:  rawData,
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return ScheduledTask{}, ErrSchedulerClosed
+	}
+
+	if err = s.config.Store.Save(ctx, task); err != nil {
+		return ScheduledTask{}, fmt.Errorf("failed to save scheduled task: %w", err)
+	}
+
+	s.mu.Lock()
+	s.push(task)
+	s.mu.Unlock()
+	s.notify()
+	return task, nil
+}
+
+func (s *schedulerImpl) ScheduleIn(ctx context.Context, name string, delay time.Duration, data any) (ScheduledTask, error) {
+	return s.Schedule(ctx, name, time.Now().Add(delay), data)
+}
+
+func (s *schedulerImpl) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	item, ok := s.tasks[id]
+	if ok {
+		heap.Remove(&s.queue, item.index)
+		delete(s.tasks, id)
+	} else if _, running := s.running[id]; running {
+		// the task is cancelled once its handler returned
+		s.running[id] = true
+		ok = true
+	}
+	s.mu.Unlock()
+	if !ok {
+		return ErrScheduledTaskNotFound
+	}
+	s.notify()
+	return s.config.Store.Delete(ctx, id)
+}
+
+func (s *schedulerImpl) Tasks() []ScheduledTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := make(scheduledQueue, len(s.queue))
+	for i, item := range s.queue {
+		queue[i] = &scheduledItem{task: item.task, index: i}
+	}
+	tasks := make([]ScheduledTask, 0, len(queue))
+	for queue.Len() > 0 {
+		tasks = append(tasks, heap.Pop(&queue).(*scheduledItem).task)
+	}
+	return tasks
+}
+
+func (s *schedulerImpl) Close(ctx context.Context) {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		// cancel the running tasks but don't wait for them again, tasks ignoring their context would block forever
+		s.runCancel()
+		s.mu.Lock()
+		abandoned := make([]string, 0, len(s.running))
+		for id := range s.running {
+			abandoned = append(abandoned, id)
+		}
+		s.mu.Unlock()
+		s.config.Logger.Warn("scheduler closed before all running tasks finished", slog.Any("task_ids", abandoned))
+	case <-done:
+		s.runCancel()
+	}
+}
+
+// push adds the given task to the queue. s.mu must be held.
+func (s *schedulerImpl) push(task ScheduledTask) {
+	if item, ok := s.tasks[task.ID]; ok {
+		item.task = task
+		heap.Fix(&s.queue, item.index)
+		return
+	}
+	item := &scheduledItem{task: task}
+	heap.Push(&s.queue, item)
+	s.tasks[task.ID] = item
+}
+
+func (s *schedulerImpl) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *schedulerImpl) loop() {
+	defer s.wg.Done()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		for s.queue.Len() > 0 && !s.queue[0].task.RunAt.After(now) {
+			item := heap.Pop(&s.queue).(*scheduledItem)
+			delete(s.tasks, item.task.ID)
+			s.running[item.task.ID] = false
+			s.wg.Add(1)
+			go s.run(item.task, s.client.TrackWork(WorkKindScheduledTask))
+		}
+		wait := time.Duration(-1)
+		if s.queue.Len() > 0 {
+			wait = s.queue[0].task.RunAt.Sub(now)
+		}
+		s.mu.Unlock()
+
+		var timerC <-chan time.Time
+		if wait >= 0 {
+			timer.Reset(wait)
+			timerC = timer.C
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.wake:
+		case <-timerC:
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	}
+}
+
+// run runs the given task and calls done once it finished, so tasks abandoned by Close are reported by Client.Shutdown.
+func (s *schedulerImpl) run(task ScheduledTask, done func()) {
+	defer s.wg.Done()
+	defer done()
+	logger := s.config.Logger.With(slog.String("task_id", task.ID), slog.String("task_name", task.Name))
+
+	err := s.handle(task)
+	if errors.Is(s.runCtx.Err(), context.Canceled) {
+		// the scheduler was closed while the task was running, it stays in the store and runs again after the next start
+		return
+	}
+
+	s.mu.Lock()
+	cancelled := s.running[task.ID]
+	delete(s.running, task.ID)
+	s.mu.Unlock()
+
+	if err == nil || cancelled {
+		if err = s.config.Store.Delete(context.Background(), task.ID); err != nil {
+			logger.Error("failed to delete finished scheduled task", slog.Any("err", err))
+		}
+		return
+	}
+
+	task.Attempts++
+	if task.Attempts > s.config.MaxRetries {
+		logger.Error("dropping scheduled task after too many attempts", slog.Int("attempts", task.Attempts), slog.Any("err", err))
+		if err = s.config.Store.Delete(context.Background(), task.ID); err != nil {
+			logger.Error("failed to delete failed scheduled task", slog.Any("err", err))
+		}
+		return
+	}
+
+	logger.Warn("scheduled task failed, retrying", slog.Int("attempts", task.Attempts), slog.Any("err", err))
+	task.RunAt = time.Now().Add(s.config.RetryDelay)
+	if err = s.config.Store.Save(context.Background(), task); err != nil {
+		logger.Error("failed to save failed scheduled task", slog.Any("err", err))
+	}
+
+	s.mu.Lock()
+	if !s.closed {
+		s.push(task)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+func (s *schedulerImpl) handle(task ScheduledTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.config.Logger.Error("recovered from panic in scheduled task handler", slog.Any("arg", r), slog.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("recovered from panic in scheduled task handler: %v", r)
+		}
+	}()
+
+	s.mu.Lock()
+	handler, ok := s.handlers[task.Name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoScheduledTaskHandler, task.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(s.runCtx, s.config.Timeout)
+	defer cancel()
+	return handler(ctx, s.client, task)
+}
+
+func newScheduledTaskID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultSchedulerConfig() schedulerConfig {
+	return schedulerConfig{
+		Logger:     slog.Default(),
+		Store:      NewMemoryScheduledTaskStore(),
+		RetryDelay: 30 * time.Second,
+		MaxRetries: 5,
+		Timeout:    time.Minute,
+	}
+}
+
+type schedulerConfig struct {
+	Logger     *slog.Logger
+	Store      ScheduledTaskStore
+	Handlers   map[string]ScheduledTaskHandler
+	RetryDelay time.Duration
+	MaxRetries int
+	Timeout    time.Duration
+}
+
+// SchedulerConfigOpt is a functional option for configuring a Scheduler.
+type SchedulerConfigOpt func(config *schedulerConfig)
+
+func (c *schedulerConfig) apply(opts []SchedulerConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_scheduler"))
+}
+
+// WithSchedulerLogger overrides the default Logger in the schedulerConfig.
+func WithSchedulerLogger(logger *slog.Logger) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithSchedulerStore sets the ScheduledTaskStore the Scheduler persists its tasks in.
+// By default, tasks are only kept in memory. Use NewFileScheduledTaskStore or your own store to keep tasks across restarts.
+func WithSchedulerStore(store ScheduledTaskStore) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		config.Store = store
+	}
+}
+
+// WithSchedulerHandler registers the ScheduledTaskHandler for tasks with the given name.
+func WithSchedulerHandler(name string, handler ScheduledTaskHandler) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		if config.Handlers == nil {
+			config.Handlers = map[string]ScheduledTaskHandler{}
+		}
+		config.Handlers[name] = handler
+	}
+}
+
+// WithSchedulerRetryDelay sets how long to wait before a failed task is run again.
+func WithSchedulerRetryDelay(delay time.Duration) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		config.RetryDelay = delay
+	}
+}
+
+// WithSchedulerMaxRetries sets how often a failed task is retried before it is dropped.
+func WithSchedulerMaxRetries(maxRetries int) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		config.MaxRetries = maxRetries
+	}
+}
+
+// WithSchedulerTimeout sets how long a ScheduledTaskHandler may run before its context is cancelled.
+func WithSchedulerTimeout(timeout time.Duration) SchedulerConfigOpt {
+	return func(config *schedulerConfig) {
+		config.Timeout = timeout
+	}
+}
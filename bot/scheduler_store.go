@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+var (
+	_ ScheduledTaskStore = (*memoryScheduledTaskStore)(nil)
+	_ ScheduledTaskStore = (*fileScheduledTaskStore)(nil)
+)
+
+// ScheduledTaskStore persists the ScheduledTask(s) of a Scheduler.
+// A ScheduledTask is only deleted from the store after its ScheduledTaskHandler succeeded, so tasks survive restarts and crashes.
+type ScheduledTaskStore interface {
+	// Save inserts or replaces the given ScheduledTask.
+	Save(ctx context.Context, task ScheduledTask) error
+
+	// Delete removes the ScheduledTask with the given ID. Deleting a task which does not exist is not an error.
+	Delete(ctx context.Context, id string) error
+
+	// All returns all stored ScheduledTask(s).
+	All(ctx context.Context) ([]ScheduledTask, error)
+}
+
+// NewMemoryScheduledTaskStore returns a ScheduledTaskStore which keeps all tasks in memory.
+// Tasks are lost when the process exits.
+func NewMemoryScheduledTaskStore() ScheduledTaskStore {
+	return &memoryScheduledTaskStore{
+		tasks: map[string]ScheduledTask{},
+	}
+}
+
+type memoryScheduledTaskStore struct {
+	mu    sync.Mutex
+	tasks map[string]ScheduledTask
+}
+
+func (s *memoryScheduledTaskStore) Save(_ context.Context, task ScheduledTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.ID] = task
+	return nil
+}
+
+func (s *memoryScheduledTaskStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tasks, id)
+	return nil
+}
+
+func (s *memoryScheduledTaskStore) All(_ context.Context) ([]ScheduledTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]ScheduledTask, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// NewFileScheduledTaskStore returns a ScheduledTaskStore which keeps all tasks in the given JSON file.
+// The file is created if it does not exist and replaced atomically on every change.
+func NewFileScheduledTaskStore(path string) ScheduledTaskStore {
+	return &fileScheduledTaskStore{
+		path: path,
+	}
+}
+
+type fileScheduledTaskStore struct {
+	path string
+
+	mu sync.Mutex
+}
+
+func (s *fileScheduledTaskStore) Save(_ context.Context, task ScheduledTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks, err := s.read()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(tasks, func(t ScheduledTask) bool {
+		return t.ID == task.ID
+	})
+	if index == -1 {
+		tasks = append(tasks, task)
+	} else {
+		tasks[index] = task
+	}
+	return s.write(tasks)
+}
+
+func (s *fileScheduledTaskStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks, err := s.read()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(tasks, func(t ScheduledTask) bool {
+		return t.ID == id
+	})
+	if index == -1 {
+		return nil
+	}
+	return s.write(slices.Delete(tasks, index, index+1))
+}
+
+func (s *fileScheduledTaskStore) All(_ context.Context) ([]ScheduledTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *fileScheduledTaskStore) read() ([]ScheduledTask, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var tasks []ScheduledTask
+	if err = json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (s *fileScheduledTaskStore) write(tasks []ScheduledTask) error {
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.path)
+}
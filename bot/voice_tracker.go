@@ -0,0 +1,350 @@
+package bot
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var _ VoiceTracker = (*voiceTrackerImpl)(nil)
+
+// VoiceChangeType is the type of VoiceChange.
+type VoiceChangeType int
+
+const (
+	// VoiceChangeJoin indicates that a member joined a channel and started a new VoiceSession.
+	VoiceChangeJoin VoiceChangeType = iota
+	// VoiceChangeMove indicates that a member moved from one channel to another.
+	VoiceChangeMove
+	// VoiceChangeLeave indicates that a member left a channel. It is always followed by a VoiceChangeSessionEnd.
+	VoiceChangeLeave
+	// VoiceChangeSessionEnd indicates that the VoiceSession of a member ended.
+	VoiceChangeSessionEnd
+	// VoiceChangeChannelOccupied indicates that the first member joined an empty channel.
+	VoiceChangeChannelOccupied
+	// VoiceChangeChannelEmpty indicates that the last member left a channel.
+	VoiceChangeChannelEmpty
+)
+
+// VoiceChange is a high level change detected by the VoiceTracker.
+type VoiceChange struct {
+	Type    VoiceChangeType
+	GuildID snowflake.ID
+	// UserID is the member which caused the change.
+	UserID snowflake.ID
+	// ChannelID is the channel which was joined, moved to, left, occupied or emptied.
+	ChannelID snowflake.ID
+	// OldChannelID is the channel the member moved from. It is only set for VoiceChangeMove.
+	OldChannelID snowflake.ID
+	// Duration is how long the member stayed in the left channel for VoiceChangeMove and VoiceChangeLeave,
+	// how long the session lasted for VoiceChangeSessionEnd and how long the channel was occupied for VoiceChangeChannelEmpty.
+	Duration time.Duration
+	// Session is the VoiceSession of the member after the change. For VoiceChangeLeave and VoiceChangeSessionEnd it is the ended session.
+	Session VoiceSession
+}
+
+// VoiceSession is the time a member spends in voice channels of a guild from joining until leaving.
+type VoiceSession struct {
+	GuildID snowflake.ID
+	UserID  snowflake.ID
+	// ChannelID is the channel the member is currently in.
+	ChannelID snowflake.ID
+	// StartedAt is when the member joined the first channel of the session.
+	// For members which were already connected when the guild was loaded, this is when the guild was loaded.
+	StartedAt time.Time
+	// ChannelJoinedAt is when the member joined the current channel.
+	ChannelJoinedAt time.Time
+	// Channels are all channels the member has been in during the session in the order they were joined.
+	Channels []snowflake.ID
+}
+
+// Duration returns how long the session is lasting so far.
+func (s VoiceSession) Duration() time.Duration {
+	return time.Since(s.StartedAt)
+}
+
+// NewVoiceTracker returns a new VoiceTracker.
+func NewVoiceTracker() VoiceTracker {
+	return &voiceTrackerImpl{
+		guilds: map[snowflake.ID]*guildVoice{},
+	}
+}
+
+// VoiceTracker tracks the VoiceSession(s) of members and the occupancy of voice channels.
+// It is updated from the gateway.EventTypeVoiceStateUpdate and gateway.EventTypeGuildCreate events and turns them into high level VoiceChange(s).
+// This requires the gateway.IntentGuildVoiceStates.
+type VoiceTracker interface {
+	// LoadGuild replaces the state of the given guild with the given voice states without reporting any changes.
+	// Sessions of members which are still in the same channel are kept.
+	LoadGuild(guildID snowflake.ID, voiceStates []discord.VoiceState)
+
+	// Update updates the VoiceSession of the member of the given voice state and returns all detected VoiceChange(s).
+	Update(voiceState discord.VoiceState) []VoiceChange
+
+	// RemoveGuild removes all sessions of the given guild without reporting any changes.
+	RemoveGuild(guildID snowflake.ID)
+
+	// Session returns the current VoiceSession of the given member.
+	Session(guildID snowflake.ID, userID snowflake.ID) (VoiceSession, bool)
+
+	// Sessions returns all current VoiceSession(s) of the given guild.
+	Sessions(guildID snowflake.ID) []VoiceSession
+
+	// ChannelMembers returns the IDs of all members in the given channel.
+	ChannelMembers(guildID snowflake.ID, channelID snowflake.ID) []snowflake.ID
+
+	// ChannelOccupancy returns the number of members in the given channel.
+	ChannelOccupancy(guildID snowflake.ID, channelID snowflake.ID) int
+
+	// GuildOccupancy returns the number of members in all voice channels of the given guild.
+	GuildOccupancy(guildID snowflake.ID) int
+
+	// OccupiedChannels returns the number of members per occupied channel of the given guild.
+	OccupiedChannels(guildID snowflake.ID) map[snowflake.ID]int
+}
+
+type voiceChannel struct {
+	members    map[snowflake.ID]struct{}
+	occupiedAt time.Time
+}
+
+type guildVoice struct {
+	sessions map[snowflake.ID]*VoiceSession
+	channels map[snowflake.ID]*voiceChannel
+}
+
+func newGuildVoice() *guildVoice {
+	return &guildVoice{
+		sessions: map[snowflake.ID]*VoiceSession{},
+		channels: map[snowflake.ID]*voiceChannel{},
+	}
+}
+
+// join adds the given user to the given channel and returns whether the channel was empty before.
+func (g *guildVoice) join(channelID snowflake.ID, userID snowflake.ID, now time.Time) bool {
+	channel, ok := g.channels[channelID]
+	if !ok {
+		channel = &voiceChannel{
+			members:    map[snowflake.ID]struct{}{},
+			occupiedAt: now,
+		}
+		g.channels[channelID] = channel
+	}
+	channel.members[userID] = struct{}{}
+	return !ok
+}
+
+// leave removes the given user from the given channel and returns how long the channel was occupied if it is empty now.
+func (g *guildVoice) leave(channelID snowflake.ID, userID snowflake.ID, now time.Time) (time.Duration, bool) {
+	channel, ok := g.channels[channelID]
+	if !ok {
+		return 0, false
+	}
+	delete(channel.members, userID)
+	if len(channel.members) > 0 {
+		return 0, false
+	}
+	delete(g.channels, channelID)
+	return now.Sub(channel.occupiedAt), true
+}
+
+type voiceTrackerImpl struct {
+	mu     sync.RWMutex
+	guilds map[snowflake.ID]*guildVoice
+}
+
+func (t *voiceTrackerImpl) LoadGuild(guildID snowflake.ID, voiceStates []discord.VoiceState) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	oldGuild := t.guilds[guildID]
+	guild := newGuildVoice()
+	for _, voiceState := range voiceStates {
+		if voiceState.ChannelID == nil {
+			continue
+		}
+		// keep sessions which survived a reconnect or the guild becoming unavailable
+		if oldGuild != nil {
+			if session, ok := oldGuild.sessions[voiceState.UserID]; ok && session.ChannelID == *voiceState.ChannelID {
+				guild.sessions[voiceState.UserID] = session
+				guild.join(session.ChannelID, voiceState.UserID, now)
+				guild.channels[session.ChannelID].occupiedAt = oldGuild.channels[session.ChannelID].occupiedAt
+				continue
+			}
+		}
+		guild.sessions[voiceState.UserID] = &VoiceSession{
+			GuildID:         guildID,
+			UserID:          voiceState.UserID,
+			ChannelID:       *voiceState.ChannelID,
+			StartedAt:       now,
+			ChannelJoinedAt: now,
+			Channels:        []snowflake.ID{*voiceState.ChannelID},
+		}
+		guild.join(*voiceState.ChannelID, voiceState.UserID, now)
+	}
+	t.guilds[guildID] = guild
+}
+
+func (t *voiceTrackerImpl) Update(voiceState discord.VoiceState) []VoiceChange {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	guild, ok := t.guilds[voiceState.GuildID]
+	if !ok {
+		guild = newGuildVoice()
+		t.guilds[voiceState.GuildID] = guild
+	}
+
+	session, hasSession := guild.sessions[voiceState.UserID]
+	var changes []VoiceChange
+	change := func(changeType VoiceChangeType, channelID snowflake.ID, duration time.Duration) {
+		var s VoiceSession
+		if session != nil {
+			s = *session
+			s.Channels = slices.Clone(session.Channels)
+		}
+		changes = append(changes, VoiceChange{
+			Type:      changeType,
+			GuildID:   voiceState.GuildID,
+			UserID:    voiceState.UserID,
+			ChannelID: channelID,
+			Duration:  duration,
+			Session:   s,
+		})
+	}
+
+	switch {
+	case voiceState.ChannelID == nil && hasSession:
+		oldChannelID := session.ChannelID
+		change(VoiceChangeLeave, oldChannelID, now.Sub(session.ChannelJoinedAt))
+		change(VoiceChangeSessionEnd, oldChannelID, now.Sub(session.StartedAt))
+		delete(guild.sessions, voiceState.UserID)
+		if occupied, empty := guild.leave(oldChannelID, voiceState.UserID, now); empty {
+			change(VoiceChangeChannelEmpty, oldChannelID, occupied)
+		}
+
+	case voiceState.ChannelID != nil && !hasSession:
+		session = &VoiceSession{
+			GuildID:         voiceState.GuildID,
+			UserID:          voiceState.UserID,
+			ChannelID:       *voiceState.ChannelID,
+			StartedAt:       now,
+			ChannelJoinedAt: now,
+			Channels:        []snowflake.ID{*voiceState.ChannelID},
+		}
+		guild.sessions[voiceState.UserID] = session
+		change(VoiceChangeJoin, session.ChannelID, 0)
+		if guild.join(session.ChannelID, voiceState.UserID, now) {
+			change(VoiceChangeChannelOccupied, session.ChannelID, 0)
+		}
+
+	case voiceState.ChannelID != nil && hasSession && *voiceState.ChannelID != session.ChannelID:
+		oldChannelID := session.ChannelID
+		duration := now.Sub(session.ChannelJoinedAt)
+		session.ChannelID = *voiceState.ChannelID
+		session.ChannelJoinedAt = now
+		session.Channels = append(session.Channels, session.ChannelID)
+		change(VoiceChangeMove, session.ChannelID, duration)
+		changes[len(changes)-1].OldChannelID = oldChannelID
+		if occupied, empty := guild.leave(oldChannelID, voiceState.UserID, now); empty {
+			change(VoiceChangeChannelEmpty, oldChannelID, occupied)
+		}
+		if guild.join(session.ChannelID, voiceState.UserID, now) {
+			change(VoiceChangeChannelOccupied, session.ChannelID, 0)
+		}
+	}
+	return changes
+}
+
+func (t *voiceTrackerImpl) RemoveGuild(guildID snowflake.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.guilds, guildID)
+}
+
+func (t *voiceTrackerImpl) Session(guildID snowflake.ID, userID snowflake.ID) (VoiceSession, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	guild, ok := t.guilds[guildID]
+	if !ok {
+		return VoiceSession{}, false
+	}
+	session, ok := guild.sessions[userID]
+	if !ok {
+		return VoiceSession{}, false
+	}
+	s := *session
+	s.Channels = slices.Clone(session.Channels)
+	return s, true
+}
+
+func (t *voiceTrackerImpl) Sessions(guildID snowflake.ID) []VoiceSession {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	guild, ok := t.guilds[guildID]
+	if !ok {
+		return nil
+	}
+	sessions := make([]VoiceSession, 0, len(guild.sessions))
+	for _, session := range guild.sessions {
+		s := *session
+		s.Channels = slices.Clone(session.Channels)
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+func (t *voiceTrackerImpl) ChannelMembers(guildID snowflake.ID, channelID snowflake.ID) []snowflake.ID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	guild, ok := t.guilds[guildID]
+	if !ok {
+		return nil
+	}
+	channel, ok := guild.channels[channelID]
+	if !ok {
+		return nil
+	}
+	return slices.Collect(maps.Keys(channel.members))
+}
+
+func (t *voiceTrackerImpl) ChannelOccupancy(guildID snowflake.ID, channelID snowflake.ID) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	guild, ok := t.guilds[guildID]
+	if !ok {
+		return 0
+	}
+	if channel, ok := guild.channels[channelID]; ok {
+		return len(channel.members)
+	}
+	return 0
+}
+
+func (t *voiceTrackerImpl) GuildOccupancy(guildID snowflake.ID) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if guild, ok := t.guilds[guildID]; ok {
+		return len(guild.sessions)
+	}
+	return 0
+}
+
+func (t *voiceTrackerImpl) OccupiedChannels(guildID snowflake.ID) map[snowflake.ID]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	occupancy := map[snowflake.ID]int{}
+	if guild, ok := t.guilds[guildID]; ok {
+		for channelID, channel := range guild.channels {
+			occupancy[channelID] = len(channel.members)
+		}
+	}
+	return occupancy
+}
@@ -61,6 +61,7 @@ type config struct {
 
 	MessageCache       MessageCache
 	MessageCachePolicy Policy[discord.Message]
+	MessageHistoryOpts []MessageHistoryOpt
 
 	EmojiCache       EmojiCache
 	EmojiCachePolicy Policy[discord.Emoji]
@@ -109,6 +110,9 @@ func (c *config) apply(opts []ConfigOpt) {
 	if c.VoiceStateCache == nil {
 		c.VoiceStateCache = NewVoiceStateCache(NewGroupedCache[discord.VoiceState](c.CacheFlags, FlagVoiceStates, c.VoiceStateCachePolicy))
 	}
+	if c.MessageCache == nil && c.MessageHistoryOpts != nil {
+		c.MessageCache = NewMessageHistoryCache(NewGroupedCache[discord.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy), c.MessageHistoryOpts...)
+	}
 	if c.MessageCache == nil {
 		c.MessageCache = NewMessageCache(NewGroupedCache[discord.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy))
 	}
@@ -288,6 +292,16 @@ func WithMessageCache(messageCache MessageCache) ConfigOpt {
 	}
 }
 
+// WithMessageHistory uses a MessageHistoryCache as MessageCache which keeps the previous versions of edited messages.
+func WithMessageHistory(opts ...MessageHistoryOpt) ConfigOpt {
+	return func(config *config) {
+		config.MessageHistoryOpts = append(config.MessageHistoryOpts, opts...)
+		if config.MessageHistoryOpts == nil {
+			config.MessageHistoryOpts = []MessageHistoryOpt{}
+		}
+	}
+}
+
 // WithEmojiCachePolicy sets the Policy[discord.Emoji] of the config.
 func WithEmojiCachePolicy(policy Policy[discord.Emoji]) ConfigOpt {
 	return func(config *config) {
@@ -836,6 +836,24 @@ type Caches interface {
 	// This requires the FlagRoles to be set.
 	MemberRoles(member discord.Member) []discord.Role
 
+	// MemberHighestRole returns the highest role of the given member, falling back to the @everyone role.
+	// This requires the FlagRoles to be set.
+	MemberHighestRole(member discord.Member) (discord.Role, bool)
+
+	// CanModerate returns an error if the actor is not allowed to kick, ban or timeout the target because of the role hierarchy or guild ownership.
+	// The returned error is either ErrGuildNotCached, ErrRoleNotCached if the highest role of a member is unknown or a *HierarchyError. Permissions are not checked, use MemberPermissions for that.
+	// This requires the FlagGuilds and FlagRoles to be set.
+	CanModerate(actor discord.Member, target discord.Member) error
+
+	// CanManageRole returns an error if the given member is not allowed to manage or assign the given role because of the role hierarchy.
+	// The returned error is either ErrGuildNotCached, ErrRoleNotCached if the highest role of the member is unknown or a *HierarchyError. Permissions are not checked, use MemberPermissions for that.
+	// This requires the FlagGuilds and FlagRoles to be set.
+	CanManageRole(member discord.Member, role discord.Role) error
+
+	// CanAssignRoles returns an error if the given member is not allowed to assign any of the given roles.
+	// This requires the FlagGuilds and FlagRoles to be set.
+	CanAssignRoles(member discord.Member, roleIDs ...snowflake.ID) error
+
 	// AudioChannelMembers returns all members which are in the given audio channel.
 	// This requires the FlagVoiceStates to be set.
 	AudioChannelMembers(channel discord.GuildAudioChannel) []discord.Member
@@ -850,6 +868,10 @@ type Caches interface {
 	// GuildMessageChannel returns a discord.GuildMessageChannel from the ChannelCache and a bool indicating if it exists.
 	GuildMessageChannel(channelID snowflake.ID) (discord.GuildMessageChannel, bool)
 
+	// MessageHistory returns the MessageHistory of an edited message and a bool indicating if it exists.
+	// This requires the MessageCache to be a MessageHistoryCache, see WithMessageHistory.
+	MessageHistory(channelID snowflake.ID, messageID snowflake.ID) (MessageHistory, bool)
+
 	// GuildThread returns a discord.GuildThread from the ChannelCache and a bool indicating if it exists.
 	GuildThread(channelID snowflake.ID) (discord.GuildThread, bool)
 
@@ -1064,6 +1086,13 @@ func (c *cachesImpl) MessageChannel(channelID snowflake.ID) (discord.MessageChan
 	return nil, false
 }
 
+func (c *cachesImpl) MessageHistory(channelID snowflake.ID, messageID snowflake.ID) (MessageHistory, bool) {
+	if historyCache, ok := c.messageCache.(MessageHistoryCache); ok {
+		return historyCache.MessageHistory(channelID, messageID)
+	}
+	return MessageHistory{}, false
+}
+
 func (c *cachesImpl) GuildMessageChannel(channelID snowflake.ID) (discord.GuildMessageChannel, bool) {
 	if ch, ok := c.Channel(channelID); ok {
 		if chM, ok := ch.(discord.GuildMessageChannel); ok {
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var (
+	// ErrGuildNotCached is returned when the guild of a hierarchy check is not cached.
+	ErrGuildNotCached = errors.New("guild not found in cache")
+	// ErrRoleNotCached is returned when a role of a hierarchy check is not cached.
+	ErrRoleNotCached = errors.New("role not found in cache")
+
+	// ErrTargetIsActor is returned when a member tries to moderate themselves.
+	ErrTargetIsActor = errors.New("actor and target are the same member")
+	// ErrTargetIsOwner is returned when the target is the owner of the guild.
+	ErrTargetIsOwner = errors.New("target is the owner of the guild")
+	// ErrTargetHigherOrEqual is returned when the highest role of the target is higher or equal to the highest role of the actor.
+	ErrTargetHigherOrEqual = errors.New("target's highest role is higher or equal to the actor's highest role")
+	// ErrRoleHigherOrEqual is returned when the role is higher or equal to the highest role of the actor.
+	ErrRoleHigherOrEqual = errors.New("role is higher or equal to the actor's highest role")
+	// ErrRoleManaged is returned when the role is managed by an integration and can't be assigned manually.
+	ErrRoleManaged = errors.New("role is managed by an integration")
+)
+
+// HierarchyError is returned when an action is not allowed because of the guild role hierarchy or ownership.
+// Use errors.Is with one of the ErrTarget* or ErrRole* errors to check for the exact reason.
+type HierarchyError struct {
+	Err            error
+	GuildID        snowflake.ID
+	ActorID        snowflake.ID
+	ActorPosition  int
+	TargetID       snowflake.ID
+	TargetPosition int
+}
+
+func (e *HierarchyError) Error() string {
+	return fmt.Sprintf("%s (guild: %s, actor: %s at position %d, target: %s at position %d)", e.Err, e.GuildID, e.ActorID, e.ActorPosition, e.TargetID, e.TargetPosition)
+}
+
+func (e *HierarchyError) Unwrap() error {
+	return e.Err
+}
+
+func (c *cachesImpl) MemberHighestRole(member discord.Member) (discord.Role, bool) {
+	var (
+		highest discord.Role
+		found   bool
+	)
+	if publicRole, ok := c.Role(member.GuildID, member.GuildID); ok {
+		highest = publicRole
+		found = true
+	}
+	for _, role := range c.MemberRoles(member) {
+		if !found || role.Compare(highest) > 0 {
+			highest = role
+			found = true
+		}
+	}
+	return highest, found
+}
+
+func (c *cachesImpl) CanModerate(actor discord.Member, target discord.Member) error {
+	guild, ok := c.Guild(actor.GuildID)
+	if !ok {
+		return ErrGuildNotCached
+	}
+
+	newErr := func(err error, actorRole discord.Role, targetRole discord.Role) error {
+		return &HierarchyError{
+			Err:            err,
+			GuildID:        guild.ID,
+			ActorID:        actor.User.ID,
+			ActorPosition:  actorRole.Position,
+			TargetID:       target.User.ID,
+			TargetPosition: targetRole.Position,
+		}
+	}
+
+	if actor.User.ID == target.User.ID {
+		return newErr(ErrTargetIsActor, discord.Role{}, discord.Role{})
+	}
+	if target.User.ID == guild.OwnerID {
+		return newErr(ErrTargetIsOwner, discord.Role{}, discord.Role{})
+	}
+	if actor.User.ID == guild.OwnerID {
+		return nil
+	}
+
+	actorRole, ok := c.MemberHighestRole(actor)
+	if !ok {
+		return fmt.Errorf("%w: highest role of %s", ErrRoleNotCached, actor.User.ID)
+	}
+	targetRole, ok := c.MemberHighestRole(target)
+	if !ok {
+		return fmt.Errorf("%w: highest role of %s", ErrRoleNotCached, target.User.ID)
+	}
+	if actorRole.Compare(targetRole) <= 0 {
+		return newErr(ErrTargetHigherOrEqual, actorRole, targetRole)
+	}
+	return nil
+}
+
+func (c *cachesImpl) CanManageRole(member discord.Member, role discord.Role) error {
+	guild, ok := c.Guild(member.GuildID)
+	if !ok {
+		return ErrGuildNotCached
+	}
+
+	memberRole, ok := c.MemberHighestRole(member)
+	if !ok && member.User.ID != guild.OwnerID {
+		return fmt.Errorf("%w: highest role of %s", ErrRoleNotCached, member.User.ID)
+	}
+	newErr := func(err error) error {
+		return &HierarchyError{
+			Err:            err,
+			GuildID:        guild.ID,
+			ActorID:        member.User.ID,
+			ActorPosition:  memberRole.Position,
+			TargetID:       role.ID,
+			TargetPosition: role.Position,
+		}
+	}
+
+	if role.Managed {
+		return newErr(ErrRoleManaged)
+	}
+	if member.User.ID == guild.OwnerID {
+		return nil
+	}
+	if memberRole.Compare(role) <= 0 {
+		return newErr(ErrRoleHigherOrEqual)
+	}
+	return nil
+}
+
+func (c *cachesImpl) CanAssignRoles(member discord.Member, roleIDs ...snowflake.ID) error {
+	for _, roleID := range roleIDs {
+		role, ok := c.Role(member.GuildID, roleID)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrRoleNotCached, roleID)
+		}
+		if err := c.CanManageRole(member, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
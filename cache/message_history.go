@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// MessageHistory contains the previous versions of an edited discord.Message.
+type MessageHistory struct {
+	// Original is the first version of the message the cache has seen.
+	Original discord.Message
+	// Revisions are the versions between the Original and the current message, oldest first.
+	// Only the most recent revisions up to the configured maximum are kept.
+	Revisions []discord.Message
+}
+
+// MessageHistoryCache is a MessageCache which keeps the previous versions of edited messages.
+type MessageHistoryCache interface {
+	MessageCache
+
+	// MessageHistory returns the MessageHistory of a message and a bool indicating if the message has been edited while cached.
+	MessageHistory(channelID snowflake.ID, messageID snowflake.ID) (MessageHistory, bool)
+}
+
+func defaultMessageHistoryConfig() messageHistoryConfig {
+	return messageHistoryConfig{
+		MaxRevisions: 5,
+	}
+}
+
+type messageHistoryConfig struct {
+	MaxRevisions          int
+	MaxMessagesPerChannel int
+	MaxAge                time.Duration
+}
+
+// MessageHistoryOpt is a type alias for a function that takes a messageHistoryConfig and is used to configure a MessageHistoryCache.
+type MessageHistoryOpt func(config *messageHistoryConfig)
+
+func (c *messageHistoryConfig) apply(opts []MessageHistoryOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithMessageHistoryMaxRevisions sets how many revisions besides the original are kept per message.
+func WithMessageHistoryMaxRevisions(maxRevisions int) MessageHistoryOpt {
+	return func(config *messageHistoryConfig) {
+		config.MaxRevisions = maxRevisions
+	}
+}
+
+// WithMessageHistoryMaxMessagesPerChannel sets how many messages are cached per channel. The oldest messages are removed first.
+// 0 means no limit.
+func WithMessageHistoryMaxMessagesPerChannel(maxMessages int) MessageHistoryOpt {
+	return func(config *messageHistoryConfig) {
+		config.MaxMessagesPerChannel = maxMessages
+	}
+}
+
+// WithMessageHistoryMaxAge sets how long after their creation messages are cached. 0 means no limit.
+// Expired messages are never returned and are removed from all channels at most once per max age when messages are added or removed.
+func WithMessageHistoryMaxAge(maxAge time.Duration) MessageHistoryOpt {
+	return func(config *messageHistoryConfig) {
+		config.MaxAge = maxAge
+	}
+}
+
+var _ MessageHistoryCache = (*messageHistoryCacheImpl)(nil)
+
+// NewMessageHistoryCache returns a new MessageHistoryCache backed by the given GroupedCache with the MessageHistoryOpt(s) applied.
+func NewMessageHistoryCache(cache GroupedCache[discord.Message], opts ...MessageHistoryOpt) MessageHistoryCache {
+	cfg := defaultMessageHistoryConfig()
+	cfg.apply(opts)
+
+	return &messageHistoryCacheImpl{
+		config:    cfg,
+		cache:     cache,
+		histories: map[snowflake.ID]MessageHistory{},
+	}
+}
+
+type messageHistoryCacheImpl struct {
+	config messageHistoryConfig
+	cache  GroupedCache[discord.Message]
+
+	mu        sync.Mutex
+	histories map[snowflake.ID]MessageHistory
+	lastSweep time.Time
+}
+
+func (c *messageHistoryCacheImpl) MessageCache() GroupedCache[discord.Message] {
+	return c.cache
+}
+
+func (c *messageHistoryCacheImpl) Message(channelID snowflake.ID, messageID snowflake.ID) (discord.Message, bool) {
+	message, ok := c.cache.Get(channelID, messageID)
+	if ok && c.expired(message) {
+		c.RemoveMessage(channelID, messageID)
+		return discord.Message{}, false
+	}
+	return message, ok
+}
+
+func (c *messageHistoryCacheImpl) Messages(channelID snowflake.ID) iter.Seq[discord.Message] {
+	c.removeExpired(channelID)
+	return c.cache.GroupAll(channelID)
+}
+
+func (c *messageHistoryCacheImpl) MessagesAllLen() int {
+	if c.config.MaxAge > 0 {
+		c.removeIf(c.expired)
+	}
+	return c.cache.Len()
+}
+
+func (c *messageHistoryCacheImpl) MessagesLen(guildID snowflake.ID) int {
+	c.removeExpired(guildID)
+	return c.cache.GroupLen(guildID)
+}
+
+func (c *messageHistoryCacheImpl) MessageHistory(channelID snowflake.ID, messageID snowflake.ID) (MessageHistory, bool) {
+	if _, ok := c.Message(channelID, messageID); !ok {
+		return MessageHistory{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history, ok := c.histories[messageID]
+	if !ok {
+		return MessageHistory{}, false
+	}
+	if history.Original.ChannelID != channelID {
+		return MessageHistory{}, false
+	}
+	return MessageHistory{
+		Original:  history.Original,
+		Revisions: slices.Clone(history.Revisions),
+	}, true
+}
+
+func (c *messageHistoryCacheImpl) AddMessage(message discord.Message) {
+	oldMessage, ok := c.cache.Get(message.ChannelID, message.ID)
+	c.cache.Put(message.ChannelID, message.ID, message)
+	if ok && isMessageEdit(oldMessage, message) {
+		c.addRevision(oldMessage)
+	}
+	c.prune(message.ChannelID)
+	c.sweep()
+}
+
+func (c *messageHistoryCacheImpl) RemoveMessage(channelID snowflake.ID, messageID snowflake.ID) (discord.Message, bool) {
+	c.mu.Lock()
+	delete(c.histories, messageID)
+	c.mu.Unlock()
+	message, ok := c.cache.Remove(channelID, messageID)
+	c.sweep()
+	return message, ok
+}
+
+func (c *messageHistoryCacheImpl) RemoveMessagesByChannelID(channelID snowflake.ID) {
+	c.removeIf(func(message discord.Message) bool {
+		return message.ChannelID == channelID
+	})
+}
+
+func (c *messageHistoryCacheImpl) RemoveMessagesByGuildID(guildID snowflake.ID) {
+	c.removeIf(func(message discord.Message) bool {
+		return message.GuildID != nil && *message.GuildID == guildID
+	})
+}
+
+func (c *messageHistoryCacheImpl) addRevision(oldMessage discord.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history, ok := c.histories[oldMessage.ID]
+	if !ok {
+		c.histories[oldMessage.ID] = MessageHistory{Original: oldMessage}
+		return
+	}
+	history.Revisions = append(history.Revisions, oldMessage)
+	if over := len(history.Revisions) - c.config.MaxRevisions; over > 0 {
+		history.Revisions = slices.Delete(history.Revisions, 0, over)
+	}
+	c.histories[oldMessage.ID] = history
+}
+
+// expired returns whether the message exceeds the configured maximum age.
+func (c *messageHistoryCacheImpl) expired(message discord.Message) bool {
+	return c.config.MaxAge > 0 && time.Since(message.ID.Time()) > c.config.MaxAge
+}
+
+// removeExpired removes the messages of the given channel which exceed the configured maximum age.
+func (c *messageHistoryCacheImpl) removeExpired(channelID snowflake.ID) {
+	if c.config.MaxAge > 0 {
+		c.groupRemoveIf(channelID, c.expired)
+	}
+}
+
+// sweep removes the expired messages of all channels, so channels without new messages don't keep them forever.
+// It runs at most once per configured maximum age.
+func (c *messageHistoryCacheImpl) sweep() {
+	if c.config.MaxAge <= 0 {
+		return
+	}
+	c.mu.Lock()
+	if time.Since(c.lastSweep) < c.config.MaxAge {
+		c.mu.Unlock()
+		return
+	}
+	c.lastSweep = time.Now()
+	c.mu.Unlock()
+	c.removeIf(c.expired)
+}
+
+// prune removes the messages of the given channel which exceed the configured maximum age or count.
+func (c *messageHistoryCacheImpl) prune(channelID snowflake.ID) {
+	c.removeExpired(channelID)
+
+	if c.config.MaxMessagesPerChannel <= 0 {
+		return
+	}
+	over := c.cache.GroupLen(channelID) - c.config.MaxMessagesPerChannel
+	if over <= 0 {
+		return
+	}
+	messageIDs := make([]snowflake.ID, 0, c.cache.GroupLen(channelID))
+	for message := range c.cache.GroupAll(channelID) {
+		messageIDs = append(messageIDs, message.ID)
+	}
+	slices.Sort(messageIDs)
+	removed := messageIDs[:min(over, len(messageIDs))]
+	for _, messageID := range removed {
+		c.cache.Remove(channelID, messageID)
+	}
+	c.removeHistories(removed)
+}
+
+func (c *messageHistoryCacheImpl) groupRemoveIf(channelID snowflake.ID, filterFunc func(message discord.Message) bool) {
+	var removed []snowflake.ID
+	c.cache.GroupRemoveIf(channelID, func(_ snowflake.ID, message discord.Message) bool {
+		if filterFunc(message) {
+			removed = append(removed, message.ID)
+			return true
+		}
+		return false
+	})
+	c.removeHistories(removed)
+}
+
+func (c *messageHistoryCacheImpl) removeIf(filterFunc func(message discord.Message) bool) {
+	var removed []snowflake.ID
+	c.cache.RemoveIf(func(_ snowflake.ID, message discord.Message) bool {
+		if filterFunc(message) {
+			removed = append(removed, message.ID)
+			return true
+		}
+		return false
+	})
+	c.removeHistories(removed)
+}
+
+func (c *messageHistoryCacheImpl) removeHistories(messageIDs []snowflake.ID) {
+	if len(messageIDs) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, messageID := range messageIDs {
+		delete(c.histories, messageID)
+	}
+}
+
+// isMessageEdit returns whether the new message is an edit of the old message and not just an update like an embed being resolved.
+func isMessageEdit(oldMessage discord.Message, newMessage discord.Message) bool {
+	if newMessage.EditedTimestamp == nil {
+		return false
+	}
+	return oldMessage.EditedTimestamp == nil || !oldMessage.EditedTimestamp.Equal(*newMessage.EditedTimestamp)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func newTestMessageHistoryCache(opts ...MessageHistoryOpt) MessageHistoryCache {
+	return NewMessageHistoryCache(NewGroupedCache[discord.Message](FlagMessages, FlagMessages, nil), opts...)
+}
+
+func testMessage(channelID snowflake.ID, createdAt time.Time) discord.Message {
+	return discord.Message{
+		ID:        snowflake.New(createdAt),
+		ChannelID: channelID,
+	}
+}
+
+func TestMessageHistoryCache_MaxMessagesPerChannel(t *testing.T) {
+	c := newTestMessageHistoryCache(WithMessageHistoryMaxMessagesPerChannel(2))
+
+	now := time.Now()
+	messages := []discord.Message{
+		testMessage(1, now.Add(-3*time.Second)),
+		testMessage(1, now.Add(-2*time.Second)),
+		testMessage(1, now.Add(-time.Second)),
+		testMessage(2, now),
+	}
+	for _, message := range messages {
+		c.AddMessage(message)
+	}
+	edited := messages[0]
+	edited.EditedTimestamp = &now
+	c.AddMessage(edited)
+
+	data := []struct {
+		message discord.Message
+		cached  bool
+	}{
+		{message: messages[0], cached: false},
+		{message: messages[1], cached: true},
+		{message: messages[2], cached: true},
+		{message: messages[3], cached: true},
+	}
+	for _, d := range data {
+		if _, ok := c.Message(d.message.ChannelID, d.message.ID); ok != d.cached {
+			t.Errorf("expected message %d cached to be %t", d.message.ID, d.cached)
+		}
+	}
+	if _, ok := c.MessageHistory(edited.ChannelID, edited.ID); ok {
+		t.Error("expected history of removed message to be removed")
+	}
+}
+
+func TestMessageHistoryCache_MaxAge(t *testing.T) {
+	const maxAge = 50 * time.Millisecond
+	c := newTestMessageHistoryCache(WithMessageHistoryMaxAge(maxAge))
+
+	c.AddMessage(testMessage(1, time.Now().Add(-2*maxAge)))
+	if n := c.MessagesAllLen(); n != 0 {
+		t.Fatalf("expected expired message not to be cached, got %d messages", n)
+	}
+
+	quiet := testMessage(1, time.Now())
+	c.AddMessage(quiet)
+	if _, ok := c.Message(quiet.ChannelID, quiet.ID); !ok {
+		t.Fatal("expected message to be cached")
+	}
+
+	time.Sleep(2 * maxAge)
+	if messages := slices.Collect(c.Messages(quiet.ChannelID)); len(messages) != 0 {
+		t.Errorf("expected expired message not to be returned, got %d messages", len(messages))
+	}
+
+	// messages of quiet channels are removed once messages of other channels are added
+	quiet = testMessage(1, time.Now())
+	c.AddMessage(quiet)
+	time.Sleep(2 * maxAge)
+	c.AddMessage(testMessage(2, time.Now()))
+	if n := c.MessageCache().Len(); n != 1 {
+		t.Errorf("expected expired message of quiet channel to be removed, got %d messages", n)
+	}
+}
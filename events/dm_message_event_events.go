@@ -3,6 +3,7 @@ package events
 import (
 	"github.com/disgoorg/snowflake/v2"
 
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 )
 
@@ -23,9 +24,13 @@ type DMMessageCreate struct {
 type DMMessageUpdate struct {
 	*GenericDMMessage
 	OldMessage discord.Message
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
 }
 
 // DMMessageDelete is called upon deleting a discord.Message in a Channel (requires gateway.IntentsDirectMessage)
 type DMMessageDelete struct {
 	*GenericDMMessage
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
 }
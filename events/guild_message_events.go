@@ -3,6 +3,7 @@ package events
 import (
 	"github.com/disgoorg/snowflake/v2"
 
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 )
 
@@ -35,9 +36,26 @@ type GuildMessageCreate struct {
 type GuildMessageUpdate struct {
 	*GenericGuildMessage
 	OldMessage discord.Message
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
 }
 
 // GuildMessageDelete is called upon deleting a discord.Message in a Channel
 type GuildMessageDelete struct {
 	*GenericGuildMessage
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
+}
+
+// GuildMessageDeleteBulk is called upon deleting multiple discord.Message(s) in a Channel at once.
+// It is dispatched after the GuildMessageDelete events of all messages.
+type GuildMessageDeleteBulk struct {
+	*GenericEvent
+	ChannelID  snowflake.ID
+	GuildID    snowflake.ID
+	MessageIDs []snowflake.ID
+	// Messages contains all deleted messages which were cached.
+	Messages map[snowflake.ID]discord.Message
+	// Histories contains the previous versions of all deleted messages which were edited while cached by a cache.MessageHistoryCache.
+	Histories map[snowflake.ID]cache.MessageHistory
 }
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+)
+
+// GenericGuildVoiceSession is called upon receiving GuildVoiceSessionJoin, GuildVoiceSessionMove, GuildVoiceSessionLeave and GuildVoiceSessionEnd (requires a bot.VoiceTracker)
+type GenericGuildVoiceSession struct {
+	*GenericEvent
+	GuildID snowflake.ID
+	Member  discord.Member
+	// Session is the bot.VoiceSession of the discord.Member after the change
+	Session bot.VoiceSession
+}
+
+// GuildVoiceSessionJoin indicates that a discord.Member joined a discord.GuildVoiceChannel and started a new bot.VoiceSession (requires a bot.VoiceTracker)
+type GuildVoiceSessionJoin struct {
+	*GenericGuildVoiceSession
+	ChannelID snowflake.ID
+}
+
+// GuildVoiceSessionMove indicates that a discord.Member moved from one discord.GuildVoiceChannel to another (requires a bot.VoiceTracker)
+type GuildVoiceSessionMove struct {
+	*GenericGuildVoiceSession
+	ChannelID    snowflake.ID
+	OldChannelID snowflake.ID
+	// Duration is how long the discord.Member stayed in the old channel
+	Duration time.Duration
+}
+
+// GuildVoiceSessionLeave indicates that a discord.Member left a discord.GuildVoiceChannel. It is always followed by a GuildVoiceSessionEnd (requires a bot.VoiceTracker)
+type GuildVoiceSessionLeave struct {
+	*GenericGuildVoiceSession
+	ChannelID snowflake.ID
+	// Duration is how long the discord.Member stayed in the left channel
+	Duration time.Duration
+}
+
+// GuildVoiceSessionEnd indicates that the bot.VoiceSession of a discord.Member ended (requires a bot.VoiceTracker)
+type GuildVoiceSessionEnd struct {
+	*GenericGuildVoiceSession
+	// Duration is how long the whole bot.VoiceSession lasted
+	Duration time.Duration
+}
+
+// GenericGuildVoiceChannelOccupancy is called upon receiving GuildVoiceChannelOccupied and GuildVoiceChannelEmpty (requires a bot.VoiceTracker)
+type GenericGuildVoiceChannelOccupancy struct {
+	*GenericEvent
+	GuildID   snowflake.ID
+	ChannelID snowflake.ID
+	// UserID is the discord.Member which joined or left the channel
+	UserID snowflake.ID
+}
+
+// GuildVoiceChannelOccupied indicates that the first discord.Member joined an empty discord.GuildVoiceChannel (requires a bot.VoiceTracker)
+type GuildVoiceChannelOccupied struct {
+	*GenericGuildVoiceChannelOccupancy
+}
+
+// GuildVoiceChannelEmpty indicates that the last discord.Member left a discord.GuildVoiceChannel (requires a bot.VoiceTracker)
+type GuildVoiceChannelEmpty struct {
+	*GenericGuildVoiceChannelOccupancy
+	// Duration is how long the channel was occupied
+	Duration time.Duration
+}
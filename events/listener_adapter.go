@@ -109,9 +109,10 @@ type ListenerAdapter struct {
 	OnGuildMemberLeave  func(event *GuildMemberLeave)
 
 	// Guild Message Events
-	OnGuildMessageCreate func(event *GuildMessageCreate)
-	OnGuildMessageUpdate func(event *GuildMessageUpdate)
-	OnGuildMessageDelete func(event *GuildMessageDelete)
+	OnGuildMessageCreate     func(event *GuildMessageCreate)
+	OnGuildMessageUpdate     func(event *GuildMessageUpdate)
+	OnGuildMessageDelete     func(event *GuildMessageDelete)
+	OnGuildMessageDeleteBulk func(event *GuildMessageDeleteBulk)
 
 	// Guild Message Reaction Events
 	OnGuildMessageReactionAdd         func(event *GuildMessageReactionAdd)
@@ -133,6 +134,12 @@ type ListenerAdapter struct {
 	OnGuildVoiceJoin              func(event *GuildVoiceJoin)
 	OnGuildVoiceMove              func(event *GuildVoiceMove)
 	OnGuildVoiceLeave             func(event *GuildVoiceLeave)
+	OnGuildVoiceSessionJoin       func(event *GuildVoiceSessionJoin)
+	OnGuildVoiceSessionMove       func(event *GuildVoiceSessionMove)
+	OnGuildVoiceSessionLeave      func(event *GuildVoiceSessionLeave)
+	OnGuildVoiceSessionEnd        func(event *GuildVoiceSessionEnd)
+	OnGuildVoiceChannelOccupied   func(event *GuildVoiceChannelOccupied)
+	OnGuildVoiceChannelEmpty      func(event *GuildVoiceChannelEmpty)
 
 	// Guild StageInstance Events
 	OnStageInstanceCreate func(event *StageInstanceCreate)
@@ -196,6 +203,8 @@ type ListenerAdapter struct {
 	OnUserActivityStart  func(event *UserActivityStart)
 	OnUserActivityUpdate func(event *UserActivityUpdate)
 	OnUserActivityStop   func(event *UserActivityStop)
+	OnUserStreamingStart func(event *UserStreamingStart)
+	OnUserStreamingStop  func(event *UserStreamingStop)
 
 	OnUserStatusUpdate       func(event *UserStatusUpdate)
 	OnUserClientStatusUpdate func(event *UserClientStatusUpdate)
@@ -494,6 +503,10 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 		if listener := l.OnGuildMessageDelete; listener != nil {
 			listener(e)
 		}
+	case *GuildMessageDeleteBulk:
+		if listener := l.OnGuildMessageDeleteBulk; listener != nil {
+			listener(e)
+		}
 
 	// Guild Message Reaction Events
 	case *GuildMessageReactionAdd:
@@ -560,6 +573,30 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 		if listener := l.OnGuildVoiceLeave; listener != nil {
 			listener(e)
 		}
+	case *GuildVoiceSessionJoin:
+		if listener := l.OnGuildVoiceSessionJoin; listener != nil {
+			listener(e)
+		}
+	case *GuildVoiceSessionMove:
+		if listener := l.OnGuildVoiceSessionMove; listener != nil {
+			listener(e)
+		}
+	case *GuildVoiceSessionLeave:
+		if listener := l.OnGuildVoiceSessionLeave; listener != nil {
+			listener(e)
+		}
+	case *GuildVoiceSessionEnd:
+		if listener := l.OnGuildVoiceSessionEnd; listener != nil {
+			listener(e)
+		}
+	case *GuildVoiceChannelOccupied:
+		if listener := l.OnGuildVoiceChannelOccupied; listener != nil {
+			listener(e)
+		}
+	case *GuildVoiceChannelEmpty:
+		if listener := l.OnGuildVoiceChannelEmpty; listener != nil {
+			listener(e)
+		}
 
 	// Guild StageInstance Events
 	case *StageInstanceCreate:
@@ -733,6 +770,14 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 		if listener := l.OnUserActivityStop; listener != nil {
 			listener(e)
 		}
+	case *UserStreamingStart:
+		if listener := l.OnUserStreamingStart; listener != nil {
+			listener(e)
+		}
+	case *UserStreamingStop:
+		if listener := l.OnUserStreamingStop; listener != nil {
+			listener(e)
+		}
 
 	// User Status Events
 	case *UserStatusUpdate:
@@ -3,6 +3,7 @@ package events
 import (
 	"github.com/disgoorg/snowflake/v2"
 
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 )
 
@@ -37,9 +38,13 @@ type MessageCreate struct {
 type MessageUpdate struct {
 	*GenericMessage
 	OldMessage discord.Message
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
 }
 
 // MessageDelete indicates that a discord.Message got deleted
 type MessageDelete struct {
 	*GenericMessage
+	// History contains the previous versions of the message if it was edited while cached by a cache.MessageHistoryCache.
+	History cache.MessageHistory
 }
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+)
+
+// ReactionVote returns a func to be used with bot.TallyVotes which converts MessageReactionAdd and MessageReactionRemove events of the given message into votes.
+// The votes are keyed by the discord.PartialEmoji.Reaction of the reaction.
+func ReactionVote(messageID snowflake.ID) func(e bot.Event) (bot.Vote[string], bool) {
+	return func(e bot.Event) (bot.Vote[string], bool) {
+		switch event := e.(type) {
+		case *MessageReactionAdd:
+			if event.MessageID == messageID {
+				return bot.Vote[string]{UserID: event.UserID, Choice: event.Emoji.Reaction()}, true
+			}
+		case *MessageReactionRemove:
+			if event.MessageID == messageID {
+				return bot.Vote[string]{UserID: event.UserID, Choice: event.Emoji.Reaction(), Remove: true}, true
+			}
+		}
+		return bot.Vote[string]{}, false
+	}
+}
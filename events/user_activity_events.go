@@ -47,3 +47,13 @@ type UserActivityUpdate struct {
 type UserActivityStop struct {
 	*GenericUserActivity
 }
+
+// UserStreamingStart indicates that a User started streaming (requires a bot.PresenceAggregator)
+type UserStreamingStart struct {
+	*GenericUserActivity
+}
+
+// UserStreamingStop indicates that a User stopped streaming (requires a bot.PresenceAggregator)
+type UserStreamingStop struct {
+	*GenericUserActivity
+}
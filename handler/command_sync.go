@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/json/v2"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// CommandSyncActionType is the type of change a CommandSyncAction applies.
+type CommandSyncActionType int
+
+const (
+	// CommandSyncActionCreate creates a command which does not exist yet.
+	CommandSyncActionCreate CommandSyncActionType = iota
+	// CommandSyncActionUpdate overwrites an existing command which differs from its definition.
+	CommandSyncActionUpdate
+	// CommandSyncActionDelete deletes an existing command which has no definition anymore.
+	CommandSyncActionDelete
+)
+
+func (t CommandSyncActionType) String() string {
+	switch t {
+	case CommandSyncActionCreate:
+		return "create"
+	case CommandSyncActionUpdate:
+		return "update"
+	case CommandSyncActionDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// CommandSyncAction is a single change of a CommandSyncPlan.
+type CommandSyncAction struct {
+	Type CommandSyncActionType
+	// GuildID is the guild of the command or nil for global commands.
+	GuildID     *snowflake.ID
+	CommandType discord.ApplicationCommandType
+	Name        string
+	// CommandID is the id of the existing command. It is 0 for CommandSyncActionCreate.
+	CommandID snowflake.ID
+	// Command is the definition which is created or updated. It is nil for CommandSyncActionDelete.
+	Command discord.ApplicationCommandCreate
+	// Changes are the names of the top level fields which differ for CommandSyncActionUpdate, like description or options.
+	Changes []string
+}
+
+func (a CommandSyncAction) String() string {
+	name := a.Name
+	if a.CommandType == discord.ApplicationCommandTypeSlash {
+		name = "/" + name
+	}
+	scope := "global"
+	if a.GuildID != nil {
+		scope = "guild " + a.GuildID.String()
+	}
+	str := fmt.Sprintf("%s %s command %s", a.Type, scope, name)
+	if len(a.Changes) > 0 {
+		str += " (" + strings.Join(a.Changes, ", ") + ")"
+	}
+	return str
+}
+
+// CommandSyncPlan is the set of changes required to sync commands, as returned by PlanCommandSync.
+type CommandSyncPlan struct {
+	Actions []CommandSyncAction
+	// Unchanged is the number of existing commands which already match their definition.
+	Unchanged int
+}
+
+// Empty returns whether the plan has no changes.
+func (p CommandSyncPlan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String returns a human-readable summary of the plan with one line per change, which is useful for reviewing the plan in CI.
+func (p CommandSyncPlan) String() string {
+	var sb strings.Builder
+	for _, action := range p.Actions {
+		sb.WriteString(action.String())
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "%d to create, %d to update, %d to delete, %d unchanged", p.count(CommandSyncActionCreate), p.count(CommandSyncActionUpdate), p.count(CommandSyncActionDelete), p.Unchanged)
+	return sb.String()
+}
+
+func (p CommandSyncPlan) count(t CommandSyncActionType) int {
+	var count int
+	for _, action := range p.Actions {
+		if action.Type == t {
+			count++
+		}
+	}
+	return count
+}
+
+// SyncCommandsDiff syncs the given commands for the given guilds or globally if guildIDs is empty like SyncCommands, but only applies the differences to the existing commands.
+// Unlike the bulk overwrite of SyncCommands, unchanged commands are left alone, which keeps their ids & permissions and saves requests.
+// If dryRun is true, nothing is changed and only the plan is returned.
+func SyncCommandsDiff(client *bot.Client, commands []discord.ApplicationCommandCreate, guildIDs []snowflake.ID, dryRun bool, opts ...rest.RequestOpt) (CommandSyncPlan, error) {
+	plan, err := PlanCommandSync(client, commands, guildIDs, opts...)
+	if err != nil || dryRun {
+		return plan, err
+	}
+	return plan, ApplyCommandSync(client, plan, opts...)
+}
+
+// PlanCommandSync fetches the existing commands with their localizations for the given guilds or globally if guildIDs is empty and compares them to the given commands.
+// Commands are matched by their type & name. Fields set by Discord like id, version or dm_permission are ignored, a missing integration_types defaults to guild install
+// and null, false, empty strings and empty lists are treated as unset before comparing, so they don't cause updates.
+// It returns on the first error for multiple guilds.
+func PlanCommandSync(client *bot.Client, commands []discord.ApplicationCommandCreate, guildIDs []snowflake.ID, opts ...rest.RequestOpt) (CommandSyncPlan, error) {
+	var plan CommandSyncPlan
+	if len(guildIDs) == 0 {
+		return plan, planCommandSync(client, &plan, commands, nil, opts...)
+	}
+	for _, guildID := range guildIDs {
+		if err := planCommandSync(client, &plan, commands, &guildID, opts...); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// ApplyCommandSync applies the given CommandSyncPlan. It returns on the first error.
+// Updates use the create endpoint, which overwrites the existing command in place and keeps its id, so fields removed from a definition are removed from the command as well.
+func ApplyCommandSync(client *bot.Client, plan CommandSyncPlan, opts ...rest.RequestOpt) error {
+	for _, action := range plan.Actions {
+		var err error
+		switch action.Type {
+		case CommandSyncActionCreate, CommandSyncActionUpdate:
+			if action.GuildID == nil {
+				_, err = client.Rest.CreateGlobalCommand(client.ApplicationID, action.Command, opts...)
+			} else {
+				_, err = client.Rest.CreateGuildCommand(client.ApplicationID, *action.GuildID, action.Command, opts...)
+			}
+		case CommandSyncActionDelete:
+			if action.GuildID == nil {
+				err = client.Rest.DeleteGlobalCommand(client.ApplicationID, action.CommandID, opts...)
+			} else {
+				err = client.Rest.DeleteGuildCommand(client.ApplicationID, *action.GuildID, action.CommandID, opts...)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", action, err)
+		}
+	}
+	return nil
+}
+
+type commandSyncKey struct {
+	commandType discord.ApplicationCommandType
+	name        string
+}
+
+func planCommandSync(client *bot.Client, plan *CommandSyncPlan, commands []discord.ApplicationCommandCreate, guildID *snowflake.ID, opts ...rest.RequestOpt) error {
+	var endpoint *rest.CompiledEndpoint
+	if guildID == nil {
+		endpoint = rest.GetGlobalCommands.Compile(discord.QueryValues{"with_localizations": true}, client.ApplicationID)
+	} else {
+		endpoint = rest.GetGuildCommands.Compile(discord.QueryValues{"with_localizations": true}, client.ApplicationID, *guildID)
+	}
+	// the raw json is compared, as the parsed commands lose the difference between unset & zero values
+	var existing []map[string]any
+	if err := client.Rest.Do(endpoint, nil, &existing, opts...); err != nil {
+		return err
+	}
+
+	existingCommands := make(map[commandSyncKey]map[string]any, len(existing))
+	for _, command := range existing {
+		commandType, _ := command["type"].(float64)
+		name, _ := command["name"].(string)
+		existingCommands[commandSyncKey{commandType: discord.ApplicationCommandType(commandType), name: name}] = command
+	}
+
+	var creates, updates []CommandSyncAction
+	seen := make(map[commandSyncKey]struct{}, len(commands))
+	for _, command := range commands {
+		key := commandSyncKey{commandType: command.Type(), name: command.CommandName()}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate command definition %q of type %d", key.name, key.commandType)
+		}
+		seen[key] = struct{}{}
+
+		action := CommandSyncAction{
+			GuildID:     guildID,
+			CommandType: key.commandType,
+			Name:        key.name,
+			Command:     command,
+		}
+		current, ok := existingCommands[key]
+		if !ok {
+			action.Type = CommandSyncActionCreate
+			creates = append(creates, action)
+			continue
+		}
+		delete(existingCommands, key)
+
+		desired, err := commandToJSONMap(command)
+		if err != nil {
+			return fmt.Errorf("failed to marshal command %q: %w", key.name, err)
+		}
+		changes := diffCommand(normalizeCommand(current), normalizeCommand(desired))
+		if len(changes) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		action.Type = CommandSyncActionUpdate
+		action.CommandID = commandID(current)
+		action.Changes = changes
+		updates = append(updates, action)
+	}
+
+	// deletes go first to free up the command limit for creates
+	for _, key := range slices.SortedFunc(maps.Keys(existingCommands), compareCommandSyncKeys) {
+		plan.Actions = append(plan.Actions, CommandSyncAction{
+			Type:        CommandSyncActionDelete,
+			GuildID:     guildID,
+			CommandType: key.commandType,
+			Name:        key.name,
+			CommandID:   commandID(existingCommands[key]),
+		})
+	}
+	plan.Actions = append(plan.Actions, updates...)
+	plan.Actions = append(plan.Actions, creates...)
+	return nil
+}
+
+func compareCommandSyncKeys(a commandSyncKey, b commandSyncKey) int {
+	if a.commandType != b.commandType {
+		return int(a.commandType) - int(b.commandType)
+	}
+	return strings.Compare(a.name, b.name)
+}
+
+func commandID(command map[string]any) snowflake.ID {
+	id, _ := command["id"].(string)
+	commandID, _ := snowflake.Parse(id)
+	return commandID
+}
+
+func commandToJSONMap(command discord.ApplicationCommandCreate) (map[string]any, error) {
+	data, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// commandSyncIgnoredFields are fields which are set by Discord or deprecated and not part of a command definition.
+var commandSyncIgnoredFields = []string{"id", "application_id", "guild_id", "version", "dm_permission", "name_localized", "description_localized"}
+
+// normalizeCommand removes fields which are not part of a command definition, fills in the default integration_types and prunes empty values,
+// so a command returned by Discord and a command definition can be compared.
+func normalizeCommand(command map[string]any) map[string]any {
+	command = maps.Clone(command)
+	for _, field := range commandSyncIgnoredFields {
+		delete(command, field)
+	}
+	command = pruneJSON(command).(map[string]any)
+	if _, ok := command["integration_types"]; !ok {
+		command["integration_types"] = []any{float64(discord.ApplicationIntegrationTypeGuildInstall)}
+	}
+	return command
+}
+
+// pruneJSON recursively removes null, false, empty strings, empty arrays & empty objects from json objects, as Discord treats them the same as unset fields.
+func pruneJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		pruned := make(map[string]any, len(v))
+		for key, value := range v {
+			value = pruneJSON(value)
+			switch value := value.(type) {
+			case nil:
+				continue
+			case bool:
+				if !value {
+					continue
+				}
+			case string:
+				if value == "" {
+					continue
+				}
+			case []any:
+				if len(value) == 0 {
+					continue
+				}
+			case map[string]any:
+				if len(value) == 0 {
+					continue
+				}
+			}
+			pruned[key] = value
+		}
+		return pruned
+	case []any:
+		pruned := make([]any, len(v))
+		for i, value := range v {
+			pruned[i] = pruneJSON(value)
+		}
+		return pruned
+	}
+	return v
+}
+
+// diffCommand returns the sorted names of the top level fields which differ between the two normalized commands.
+func diffCommand(current map[string]any, desired map[string]any) []string {
+	var changes []string
+	for key, value := range desired {
+		if !reflect.DeepEqual(current[key], value) {
+			changes = append(changes, key)
+		}
+	}
+	for key := range current {
+		if _, ok := desired[key]; !ok {
+			changes = append(changes, key)
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// commandSyncRest returns the given existing commands for every request.
+type commandSyncRest struct {
+	rest.Rest
+	existing string
+}
+
+func (r *commandSyncRest) Do(_ *rest.CompiledEndpoint, _ any, rsBody any, _ ...rest.RequestOpt) error {
+	return json.Unmarshal([]byte(r.existing), rsBody)
+}
+
+func TestNormalizeCommand(t *testing.T) {
+	data := []struct {
+		name     string
+		command  string
+		expected string
+	}{
+		{
+			name:     "ignored fields",
+			command:  `{"id":"1","application_id":"2","guild_id":"3","version":"4","dm_permission":true,"name":"ping","name_localized":"ping","description_localized":"Ping","integration_types":[0]}`,
+			expected: `{"name":"ping","integration_types":[0]}`,
+		},
+		{
+			name:     "prune false, empty string and null",
+			command:  `{"name":"ping","nsfw":false,"description":"","contexts":null,"options":[],"name_localizations":{},"integration_types":[0]}`,
+			expected: `{"name":"ping","integration_types":[0]}`,
+		},
+		{
+			name:     "prune nested options",
+			command:  `{"name":"ping","options":[{"name":"user","required":false,"choices":null}],"integration_types":[0]}`,
+			expected: `{"name":"ping","options":[{"name":"user"}],"integration_types":[0]}`,
+		},
+		{
+			name:     "default integration types",
+			command:  `{"name":"ping"}`,
+			expected: `{"name":"ping","integration_types":[0]}`,
+		},
+		{
+			name:     "keep integration types",
+			command:  `{"name":"ping","integration_types":[1]}`,
+			expected: `{"name":"ping","integration_types":[1]}`,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			normalized := normalizeCommand(unmarshalCommand(t, d.command))
+			if expected := unmarshalCommand(t, d.expected); !reflect.DeepEqual(normalized, expected) {
+				t.Errorf("expected %v, got %v", expected, normalized)
+			}
+		})
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	data := []struct {
+		name     string
+		current  string
+		desired  string
+		expected []string
+	}{
+		{
+			name:    "unchanged",
+			current: `{"name":"ping","description":"Ping"}`,
+			desired: `{"name":"ping","description":"Ping"}`,
+		},
+		{
+			name:     "changed",
+			current:  `{"name":"ping","description":"Ping","nsfw":true}`,
+			desired:  `{"name":"ping","description":"Pong","nsfw":true}`,
+			expected: []string{"description"},
+		},
+		{
+			name:     "added and removed",
+			current:  `{"name":"ping","options":[{"name":"user"}]}`,
+			desired:  `{"name":"ping","description":"Ping"}`,
+			expected: []string{"description", "options"},
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			changes := diffCommand(unmarshalCommand(t, d.current), unmarshalCommand(t, d.desired))
+			if !slices.Equal(changes, d.expected) {
+				t.Errorf("expected %v, got %v", d.expected, changes)
+			}
+		})
+	}
+}
+
+func TestPlanCommandSync(t *testing.T) {
+	client := &bot.Client{
+		Rest: &commandSyncRest{existing: `[
+			{"id":"1","application_id":"10","version":"1","type":1,"name":"ping","description":"Ping","dm_permission":true,"contexts":null,"integration_types":[0],"nsfw":false},
+			{"id":"2","application_id":"10","version":"1","type":1,"name":"echo","description":"Echo","integration_types":[0]},
+			{"id":"3","application_id":"10","version":"1","type":1,"name":"old","description":"Old","integration_types":[0]},
+			{"id":"4","application_id":"10","version":"1","type":2,"name":"Info","description":"","integration_types":[0]}
+		]`},
+	}
+
+	commands := []discord.ApplicationCommandCreate{
+		discord.SlashCommandCreate{Name: "ping", Description: "Ping"},
+		discord.SlashCommandCreate{Name: "echo", Description: "Echo a message"},
+		discord.SlashCommandCreate{Name: "new", Description: "New"},
+		discord.UserCommandCreate{Name: "Info"},
+	}
+	plan, err := PlanCommandSync(client, commands, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"delete global command /old",
+		"update global command /echo (description)",
+		"create global command /new",
+	}
+	actions := make([]string, len(plan.Actions))
+	for i, action := range plan.Actions {
+		actions[i] = action.String()
+	}
+	if !slices.Equal(actions, expected) {
+		t.Errorf("expected actions %v, got %v", expected, actions)
+	}
+	if plan.Unchanged != 2 {
+		t.Errorf("expected 2 unchanged commands, got %d", plan.Unchanged)
+	}
+	if plan.Actions[0].CommandID != 3 || plan.Actions[1].CommandID != 2 {
+		t.Errorf("expected command ids 3 and 2, got %d and %d", plan.Actions[0].CommandID, plan.Actions[1].CommandID)
+	}
+
+	if _, err = PlanCommandSync(client, append(commands, discord.SlashCommandCreate{Name: "ping", Description: "Ping"}), nil); err == nil {
+		t.Error("expected error for duplicate command definitions")
+	}
+}
+
+func unmarshalCommand(t *testing.T, data string) map[string]any {
+	t.Helper()
+	var command map[string]any
+	if err := json.Unmarshal([]byte(data), &command); err != nil {
+		t.Fatal(err)
+	}
+	return command
+}
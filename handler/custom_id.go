@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxCustomIDLength is the maximum length of the custom id of a component or modal in characters.
+const MaxCustomIDLength = 100
+
+// CustomIDLengthError is returned by CustomID.Encode when the encoded custom id exceeds MaxCustomIDLength.
+type CustomIDLengthError struct {
+	CustomID string
+}
+
+func (e *CustomIDLengthError) Error() string {
+	length := utf8.RuneCountInString(e.CustomID)
+	return fmt.Sprintf("custom id is %d characters long, which exceeds the limit of %d by %d", length, MaxCustomIDLength, length-MaxCustomIDLength)
+}
+
+// CustomIDComponentHandler is a function that handles component interactions with the custom id decoded into the data struct T.
+type CustomIDComponentHandler[T any] func(data T, e *ComponentEvent) error
+
+// CustomIDModalHandler is a function that handles modal interactions with the custom id decoded into the data struct T.
+type CustomIDModalHandler[T any] func(data T, e *ModalEvent) error
+
+// CustomID encodes the fields of a struct T into compact custom ids of components and modals and decodes them again.
+// A custom id consists of the prefix followed by one path segment per field, like /menu/close/2kbg1dlz0ge8/1.
+//
+// Supported field types are strings, bools, signed & unsigned integers and types based on them, like snowflake.ID or enums.
+// Integers are encoded in base 36, bools as 1 or 0 and slashes in strings are escaped.
+// Unexported fields and fields tagged with `customid:"-"` are skipped.
+type CustomID[T any] struct {
+	prefix string
+	fields []customIDField
+}
+
+type customIDField struct {
+	index int
+	name  string
+}
+
+// NewCustomID returns a new CustomID for T with the given prefix, which must start with / like route patterns.
+// It panics if T is not a struct or contains unsupported fields.
+func NewCustomID[T any](prefix string) *CustomID[T] {
+	checkPattern(prefix)
+	if strings.ContainsAny(prefix, "{}") {
+		panic("custom id prefix must not contain variables")
+	}
+	dataType := reflect.TypeFor[T]()
+	if dataType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("custom id data must be a struct, got %s", dataType))
+	}
+
+	var fields []customIDField
+	for i := range dataType.NumField() {
+		field := dataType.Field(i)
+		if !field.IsExported() || field.Tag.Get("customid") == "-" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			panic(fmt.Sprintf("unsupported custom id field %s of type %s", field.Name, field.Type))
+		}
+		fields = append(fields, customIDField{index: i, name: field.Name})
+	}
+
+	return &CustomID[T]{
+		prefix: strings.TrimSuffix(prefix, "/"),
+		fields: fields,
+	}
+}
+
+// Prefix returns the prefix of the custom ids.
+func (c *CustomID[T]) Prefix() string {
+	return c.prefix
+}
+
+// Pattern returns the route pattern matching the custom ids, which has one variable per field named after the field.
+func (c *CustomID[T]) Pattern() string {
+	var sb strings.Builder
+	sb.WriteString(c.prefix)
+	for _, field := range c.fields {
+		sb.WriteString("/{")
+		sb.WriteString(field.name)
+		sb.WriteString("}")
+	}
+	return sb.String()
+}
+
+// Encode returns the custom id for the given data. It returns a *CustomIDLengthError if the custom id is longer than MaxCustomIDLength.
+func (c *CustomID[T]) Encode(data T) (string, error) {
+	value := reflect.ValueOf(data)
+
+	var sb strings.Builder
+	sb.WriteString(c.prefix)
+	for _, field := range c.fields {
+		sb.WriteString("/")
+		sb.WriteString(encodeCustomIDValue(value.Field(field.index)))
+	}
+
+	customID := sb.String()
+	if utf8.RuneCountInString(customID) > MaxCustomIDLength {
+		return "", &CustomIDLengthError{CustomID: customID}
+	}
+	return customID, nil
+}
+
+// MustEncode is like Encode but panics if the custom id is too long.
+func (c *CustomID[T]) MustEncode(data T) string {
+	customID, err := c.Encode(data)
+	if err != nil {
+		panic(err)
+	}
+	return customID
+}
+
+// Decode decodes the given custom id into T.
+func (c *CustomID[T]) Decode(customID string) (T, error) {
+	var data T
+	rest, ok := strings.CutPrefix(customID, c.prefix)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return data, fmt.Errorf("custom id %q does not start with %q", customID, c.prefix)
+	}
+	parts := splitPath(rest)
+	if rest == "" {
+		parts = nil
+	}
+	if len(parts) != len(c.fields) {
+		return data, fmt.Errorf("custom id %q has %d fields, expected %d", customID, len(parts), len(c.fields))
+	}
+
+	vars := make(map[string]string, len(c.fields))
+	for i, field := range c.fields {
+		vars[field.name] = parts[i]
+	}
+	return c.decodeVars(vars)
+}
+
+func (c *CustomID[T]) decodeVars(vars map[string]string) (T, error) {
+	var data T
+	value := reflect.ValueOf(&data).Elem()
+	for _, field := range c.fields {
+		if err := decodeCustomIDValue(value.Field(field.index), vars[field.name]); err != nil {
+			return data, fmt.Errorf("failed to decode custom id field %s: %w", field.name, err)
+		}
+	}
+	return data, nil
+}
+
+// Component registers the given CustomIDComponentHandler for the custom ids of this CustomID to the given Router.
+// The custom ids are matched with Pattern, so the Router must not be nested in a Route with another prefix.
+func (c *CustomID[T]) Component(r Router, h CustomIDComponentHandler[T]) {
+	r.Component(c.Pattern(), func(e *ComponentEvent) error {
+		data, err := c.decodeVars(e.Vars)
+		if err != nil {
+			return err
+		}
+		return h(data, e)
+	})
+}
+
+// Modal registers the given CustomIDModalHandler for the custom ids of this CustomID to the given Router.
+// The custom ids are matched with Pattern, so the Router must not be nested in a Route with another prefix.
+func (c *CustomID[T]) Modal(r Router, h CustomIDModalHandler[T]) {
+	r.Modal(c.Pattern(), func(e *ModalEvent) error {
+		data, err := c.decodeVars(e.Vars)
+		if err != nil {
+			return err
+		}
+		return h(data, e)
+	})
+}
+
+var (
+	customIDEscaper   = strings.NewReplacer("%", "%25", "/", "%2F")
+	customIDUnescaper = strings.NewReplacer("%2F", "/", "%25", "%")
+)
+
+func encodeCustomIDValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return customIDEscaper.Replace(value.String())
+	case reflect.Bool:
+		if value.Bool() {
+			return "1"
+		}
+		return "0"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 36)
+	default:
+		return strconv.FormatUint(value.Uint(), 36)
+	}
+}
+
+func decodeCustomIDValue(value reflect.Value, str string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(customIDUnescaper.Replace(str))
+	case reflect.Bool:
+		switch str {
+		case "1":
+			value.SetBool(true)
+		case "0":
+			value.SetBool(false)
+		default:
+			return fmt.Errorf("invalid bool %q", str)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 36, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(str, 36, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(u)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type customIDData struct {
+	Name    string
+	Enabled bool
+	Count   int
+	Small   int8
+	ID      snowflake.ID
+	skipped string
+	Skipped string `customid:"-"`
+}
+
+func TestCustomID_RoundTrip(t *testing.T) {
+	customID := NewCustomID[customIDData]("/test")
+
+	data := []customIDData{
+		{},
+		{Name: "name", Enabled: true, Count: 42, Small: 127, ID: 1234567890123456789},
+		{Name: "a/b/c", Count: -42, Small: -128},
+		{Name: "100%"},
+		{Name: "%2F/%25"},
+		{Name: "//", ID: snowflake.ID(^uint64(0))},
+	}
+
+	for _, d := range data {
+		encoded, err := customID.Encode(d)
+		if err != nil {
+			t.Fatalf("failed to encode %+v: %s", d, err)
+		}
+		decoded, err := customID.Decode(encoded)
+		if err != nil {
+			t.Fatalf("failed to decode %q: %s", encoded, err)
+		}
+		if decoded != d {
+			t.Errorf("expected %+v, got %+v from %q", d, decoded, encoded)
+		}
+	}
+}
+
+func TestCustomID_SkippedFields(t *testing.T) {
+	customID := NewCustomID[customIDData]("/test")
+	encoded := customID.MustEncode(customIDData{skipped: "skipped", Skipped: "skipped"})
+	if strings.Contains(encoded, "skipped") {
+		t.Errorf("expected skipped fields not to be encoded, got %q", encoded)
+	}
+	if pattern := customID.Pattern(); pattern != "/test/{Name}/{Enabled}/{Count}/{Small}/{ID}" {
+		t.Errorf("unexpected pattern %q", pattern)
+	}
+}
+
+func TestCustomID_DecodeErrors(t *testing.T) {
+	customID := NewCustomID[customIDData]("/test")
+
+	data := []string{
+		"/other/name/1/0/0/0",
+		"/testing/name/1/0/0/0",
+		"/test/name/1/0/0",
+		"/test/name/1/0/0/0/0",
+		"/test/name/2/0/0/0",
+		"/test/name/1/zzzzzzzzzzzzzzzzzzzzzzzz/0/0",
+		"/test/name/1/0/3k/0",
+		"/test/name/1/0/-3l/0",
+		"/test/name/1/0/0/-1",
+		"/test/name/1/0/0/zzzzzzzzzzzzzzzzzzzz",
+	}
+
+	for _, d := range data {
+		if decoded, err := customID.Decode(d); err == nil {
+			t.Errorf("expected an error for %q, got %+v", d, decoded)
+		}
+	}
+}
+
+func TestCustomID_LengthError(t *testing.T) {
+	customID := NewCustomID[customIDData]("/test")
+
+	encoded, err := customID.Encode(customIDData{Name: strings.Repeat("a", MaxCustomIDLength)})
+	var lengthErr *CustomIDLengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("expected a CustomIDLengthError, got %q, %v", encoded, err)
+	}
+	if len(lengthErr.CustomID) <= MaxCustomIDLength {
+		t.Errorf("expected the custom id of the error to exceed %d characters, got %d", MaxCustomIDLength, len(lengthErr.CustomID))
+	}
+
+	// the length is counted in characters, not bytes
+	if encoded, err = customID.Encode(customIDData{Name: strings.Repeat("ä", 80)}); err != nil {
+		t.Errorf("expected non-ASCII custom id of %d characters to be valid, got %v", utf8.RuneCountInString(encoded), err)
+	}
+	if _, err = customID.Encode(customIDData{Name: strings.Repeat("ä", MaxCustomIDLength)}); !errors.As(err, &lengthErr) {
+		t.Errorf("expected a CustomIDLengthError for non-ASCII custom id, got %v", err)
+	} else if expected := fmt.Sprintf("custom id is %d characters long", utf8.RuneCountInString(lengthErr.CustomID)); !strings.HasPrefix(lengthErr.Error(), expected) {
+		t.Errorf("expected error to start with %q, got %q", expected, lengthErr.Error())
+	}
+
+	// escaping counts towards the length
+	if _, err = customID.Encode(customIDData{Name: strings.Repeat("/", 30)}); !errors.As(err, &lengthErr) {
+		t.Errorf("expected a CustomIDLengthError for escaped slashes, got %v", err)
+	}
+}
@@ -49,4 +49,18 @@
 // - [Router.Group]: to create a new router and add it to the current router
 // - [Router.Route]: to create a new sub-router with the given pattern and add it to the current router
 // - [Router.Mount]: to mount the given router with the given pattern to the current router
+//
+// Slash commands can be defined declaratively with [NewSlashCommand] and [NewSlashCommandGroup].
+// The command options are derived from an annotated args struct, which is also used to decode the options of the interaction.
+// [SlashCommandDefinition.Register] registers the handlers and [SlashCommandCreates] returns the commands to sync with [SyncCommands].
+//
+// The [InteractionEvent] tracks whether the interaction is unacknowledged, deferred, responded or showed a modal.
+// Responding twice returns an [InteractionStateError] instead of an error from Discord.
+// [InteractionEvent.Reply], [InteractionEvent.Edit] and [InteractionEvent.Followup] pick the right call for the current [InteractionState].
+//
+// Structured state can be stored in the custom id of components and modals with [NewCustomID].
+// [CustomID.Encode] encodes a struct into a compact custom id and [CustomID.Component] or [CustomID.Modal] register a handler which receives the decoded struct.
+// State which does not fit into a custom id can be kept server-side in a [Session] of a [SessionManager], whose token is passed in the {session} route variable.
+//
+// Handlers can be tested without connecting to Discord with the [github.com/disgoorg/disgo/handler/handlertest] package, which dispatches synthetic interactions through a [Mux] and records all responses.
 package handler
@@ -31,6 +31,7 @@ import (
 )
 
 // SyncCommands sets the given commands for the given guilds or globally if no guildIDs are empty. It will return on the first error for multiple guilds.
+// This bulk overwrites all commands, use SyncCommandsDiff to only apply the differences to the existing commands.
 func SyncCommands(client *bot.Client, commands []discord.ApplicationCommandCreate, guildIDs []snowflake.ID, opts ...rest.RequestOpt) error {
 	if len(guildIDs) == 0 {
 		_, err := client.Rest.SetGlobalCommands(client.ApplicationID, commands, opts...)
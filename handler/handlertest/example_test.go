@@ -0,0 +1,51 @@
+package handlertest_test
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+func Example() {
+	mux := handler.New()
+	mux.SlashCommand("/greet", func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		return e.CreateMessage(discord.MessageCreate{Content: "Hello " + data.String("name") + "!"})
+	})
+
+	h := handlertest.New(mux)
+	rec := h.Dispatch(handlertest.SlashCommand("/greet", handlertest.WithStringOption("name", "Bob")))
+	fmt.Println(rec.Err())
+	fmt.Println(rec.Message().Content)
+	// Output:
+	// <nil>
+	// Hello Bob!
+}
+
+func ExampleRecording_Calls() {
+	mux := handler.New()
+	mux.Command("/report", func(e *handler.CommandEvent) error {
+		if err := e.DeferCreateMessage(false); err != nil {
+			return err
+		}
+		if _, err := e.UpdateInteractionResponse(discord.NewMessageUpdateBuilder().SetContent("Report is ready").Build()); err != nil {
+			return err
+		}
+		_, err := e.CreateFollowupMessage(discord.MessageCreate{Content: "Here are the details"})
+		return err
+	})
+
+	rec := handlertest.New(mux).Dispatch(handlertest.SlashCommand("/report"))
+	for _, call := range rec.Calls() {
+		fmt.Println(call.Type)
+	}
+	fmt.Println(rec.Message().Content)
+	fmt.Println(rec.Followups()[0].Content)
+	// Output:
+	// create response
+	// update response
+	// create followup
+	// Report is ready
+	// Here are the details
+}
@@ -0,0 +1,104 @@
+// Package handlertest provides utilities for testing handlers of a handler.Mux without connecting to Discord.
+//
+// Interactions are built with SlashCommand, UserCommand, MessageCommand, Autocomplete, Button, StringSelectMenu, SelectMenu and Modal
+// and dispatched through the Mux by a Harness. Every response, edit and followup message is recorded into a Recording instead of being sent to Discord:
+//
+//	mux := handler.New()
+//	mux.SlashCommand("/greet", greetHandler)
+//
+//	h := handlertest.New(mux)
+//	rec := h.Dispatch(handlertest.SlashCommand("/greet", handlertest.WithStringOption("name", "Bob")))
+//	if rec.Err() != nil {
+//		t.Fatal(rec.Err())
+//	}
+//	if message := rec.Message(); message == nil || message.Content != "Hello Bob!" {
+//		t.Errorf("unexpected response: %+v", rec.Response())
+//	}
+//
+// Built interactions are triggered by DefaultUser in DefaultChannelID of DefaultGuildID, which can be changed with InteractionOpt(s) like WithUser, WithPermissions or WithDM.
+// Requests other than interaction responses and followup messages fail with ErrUnsupportedRequest, unless a rest.Rest is set with WithRest.
+package handlertest
+
+import (
+	"sync"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// New returns a new Harness which dispatches interactions through the given handler.Mux.
+// The handler.ErrorHandler of the Mux is replaced to record the errors returned by handlers, use WithErrorHandler to handle them as well.
+func New(mux *handler.Mux, opts ...ConfigOpt) *Harness {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	h := &Harness{
+		mux:        mux,
+		config:     cfg,
+		recordings: map[string]*Recording{},
+	}
+	h.Client = &bot.Client{
+		ApplicationID: DefaultApplicationID,
+		Logger:        cfg.Logger,
+		Rest: &recordingRest{
+			Rest:    cfg.Rest,
+			harness: h,
+		},
+		Caches: cfg.Caches,
+	}
+	mux.Error(h.handleError)
+	return h
+}
+
+// Harness dispatches interactions through a handler.Mux and records everything its handlers do with them.
+type Harness struct {
+	// Client is the bot.Client of the dispatched events. Its rest.Rest records all interaction responses and followup messages.
+	Client *bot.Client
+
+	mux    *handler.Mux
+	config config
+
+	mu         sync.Mutex
+	recordings map[string]*Recording
+}
+
+// Dispatch dispatches the given interaction through the handler.Mux and returns the Recording of it once the Mux has handled it.
+// The interaction should be built with one of the builders of this package, each interaction can only be dispatched once.
+func (h *Harness) Dispatch(interaction discord.Interaction) *Recording {
+	recording := &Recording{
+		Interaction: interaction,
+	}
+	h.mu.Lock()
+	h.recordings[interaction.Token()] = recording
+	h.mu.Unlock()
+
+	h.mux.OnEvent(&events.InteractionCreate{
+		GenericEvent: events.NewGenericEvent(h.Client, -1, -1),
+		Interaction:  interaction,
+		Respond: func(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+			return h.Client.Rest.CreateInteractionResponse(interaction.ID(), interaction.Token(), discord.InteractionResponse{
+				Type: responseType,
+				Data: data,
+			}, opts...)
+		},
+	})
+	return recording
+}
+
+func (h *Harness) recording(interactionToken string) *Recording {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.recordings[interactionToken]
+}
+
+func (h *Harness) handleError(e *handler.InteractionEvent, err error) {
+	if recording := h.recording(e.Token()); recording != nil {
+		recording.setErr(err)
+	}
+	if h.config.ErrorHandler != nil {
+		h.config.ErrorHandler(e, err)
+	}
+}
@@ -0,0 +1,67 @@
+package handlertest
+
+import (
+	"log/slog"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger: slog.Default(),
+	}
+}
+
+type config struct {
+	Logger       *slog.Logger
+	Rest         rest.Rest
+	Caches       cache.Caches
+	ErrorHandler handler.ErrorHandler
+}
+
+// ConfigOpt is a type alias for a function that takes a config and is used to configure your Harness.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Rest == nil {
+		c.Rest = rest.New(unsupportedClient{})
+	}
+	if c.Caches == nil {
+		c.Caches = cache.New()
+	}
+	c.Logger = c.Logger.With(slog.String("name", "handlertest"))
+}
+
+// WithLogger overrides the default Logger of the bot.Client of the Harness.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithRest sets the rest.Rest used for all requests except interaction responses and followup messages, which are always recorded.
+// By default, these requests fail with ErrUnsupportedRequest.
+func WithRest(rest rest.Rest) ConfigOpt {
+	return func(config *config) {
+		config.Rest = rest
+	}
+}
+
+// WithCaches sets the cache.Caches of the bot.Client of the Harness, for example to prefill channels or guilds. By default, empty caches are used.
+func WithCaches(caches cache.Caches) ConfigOpt {
+	return func(config *config) {
+		config.Caches = caches
+	}
+}
+
+// WithErrorHandler sets a handler.ErrorHandler which is called after an error returned by a handler has been recorded.
+func WithErrorHandler(errorHandler handler.ErrorHandler) ConfigOpt {
+	return func(config *config) {
+		config.ErrorHandler = errorHandler
+	}
+}
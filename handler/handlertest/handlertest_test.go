@@ -0,0 +1,287 @@
+package handlertest_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+func dispatch(t *testing.T, mux *handler.Mux, interaction discord.Interaction) *handlertest.Recording {
+	t.Helper()
+	rec := handlertest.New(mux).Dispatch(interaction)
+	if rec.Err() != nil {
+		t.Fatalf("unexpected error: %s", rec.Err())
+	}
+	return rec
+}
+
+func TestSlashCommand(t *testing.T) {
+	mux := handler.New()
+	mux.SlashCommand("/settings/language/set", func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		if language := data.String("language"); language != "de" {
+			t.Errorf("expected language option de, got %q", language)
+		}
+		if e.User().ID != handlertest.DefaultUser.ID {
+			t.Errorf("expected default user, got %d", e.User().ID)
+		}
+		if guildID := e.GuildID(); guildID == nil || *guildID != handlertest.DefaultGuildID {
+			t.Errorf("expected default guild, got %v", guildID)
+		}
+		if member := e.Member(); member == nil || !member.Permissions.Has(discord.PermissionManageGuild) {
+			t.Errorf("expected member with manage guild permission, got %+v", member)
+		}
+		return e.CreateMessage(discord.MessageCreate{Content: "ok"})
+	})
+
+	dispatch(t, mux, handlertest.SlashCommand("/settings/language/set",
+		handlertest.WithStringOption("language", "de"),
+		handlertest.WithPermissions(discord.PermissionManageGuild),
+	))
+}
+
+func TestSlashCommand_DM(t *testing.T) {
+	mux := handler.New()
+	mux.Command("/ping", func(e *handler.CommandEvent) error {
+		if guildID := e.GuildID(); guildID != nil {
+			t.Errorf("expected no guild, got %d", *guildID)
+		}
+		if e.Member() != nil {
+			t.Error("expected no member")
+		}
+		return e.CreateMessage(discord.MessageCreate{Content: "pong"})
+	})
+
+	dispatch(t, mux, handlertest.SlashCommand("/ping", handlertest.WithDM()))
+}
+
+func TestUserAndMessageCommand(t *testing.T) {
+	target := discord.User{ID: 1, Username: "target"}
+	message := discord.Message{ID: 2, ChannelID: handlertest.DefaultChannelID, Content: "message"}
+
+	mux := handler.New()
+	mux.UserCommand("/info", func(data discord.UserCommandInteractionData, e *handler.CommandEvent) error {
+		if user := data.TargetUser(); user.ID != target.ID {
+			t.Errorf("expected target user %d, got %d", target.ID, user.ID)
+		}
+		return e.CreateMessage(discord.MessageCreate{Content: "info"})
+	})
+	mux.MessageCommand("/quote", func(data discord.MessageCommandInteractionData, e *handler.CommandEvent) error {
+		if m := data.TargetMessage(); m.ID != message.ID || m.Content != message.Content {
+			t.Errorf("expected target message %+v, got %+v", message, m)
+		}
+		return e.CreateMessage(discord.MessageCreate{Content: "quote"})
+	})
+
+	dispatch(t, mux, handlertest.UserCommand("info", target))
+	dispatch(t, mux, handlertest.MessageCommand("quote", message))
+}
+
+func TestAutocomplete(t *testing.T) {
+	mux := handler.New()
+	mux.Autocomplete("/search", func(e *handler.AutocompleteEvent) error {
+		if focused := e.Data.Focused(); focused.Name != "query" || focused.String() != "dis" {
+			t.Errorf("expected focused option query, got %+v", focused)
+		}
+		return e.AutocompleteResult([]discord.AutocompleteChoice{
+			discord.AutocompleteChoiceString{Name: "disgo", Value: "disgo"},
+		})
+	})
+
+	rec := dispatch(t, mux, handlertest.Autocomplete("/search", handlertest.WithFocusedOption("query", "dis")))
+	if responseType, ok := rec.ResponseType(); !ok || responseType != discord.InteractionResponseTypeAutocompleteResult {
+		t.Errorf("expected autocomplete result, got %d", responseType)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	userIDs := []snowflake.ID{1, 2}
+
+	mux := handler.New()
+	mux.ButtonComponent("/button/{id}", func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		if id := e.Vars["id"]; id != "42" {
+			t.Errorf("expected id 42, got %q", id)
+		}
+		return e.DeferUpdateMessage()
+	})
+	mux.SelectMenuComponent("/string", func(data discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+		if values := e.StringSelectMenuInteractionData().Values; !slices.Equal(values, []string{"a", "b"}) {
+			t.Errorf("expected values a and b, got %v", values)
+		}
+		return e.DeferUpdateMessage()
+	})
+	mux.SelectMenuComponent("/users", func(data discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+		if values := e.UserSelectMenuInteractionData().Values; !slices.Equal(values, userIDs) {
+			t.Errorf("expected values %v, got %v", userIDs, values)
+		}
+		return e.DeferUpdateMessage()
+	})
+	mux.Modal("/modal", func(e *handler.ModalEvent) error {
+		if text := e.Data.Text("name"); text != "disgo" {
+			t.Errorf("expected text input disgo, got %q", text)
+		}
+		return e.CreateMessage(discord.MessageCreate{Content: "submitted"})
+	})
+
+	dispatch(t, mux, handlertest.Button("/button/42"))
+	dispatch(t, mux, handlertest.StringSelectMenu("/string", []string{"a", "b"}))
+	dispatch(t, mux, handlertest.SelectMenu(discord.ComponentTypeUserSelectMenu, "/users", userIDs))
+	dispatch(t, mux, handlertest.Modal("/modal", handlertest.WithTextInput("name", "disgo")))
+}
+
+func TestRecording_DeferEditFollowup(t *testing.T) {
+	mux := handler.New()
+	mux.Command("/slow", func(e *handler.CommandEvent) error {
+		if err := e.DeferCreateMessage(true); err != nil {
+			return err
+		}
+		if _, err := e.UpdateInteractionResponse(discord.NewMessageUpdateBuilder().SetContent("done").Build()); err != nil {
+			return err
+		}
+		followup, err := e.CreateFollowupMessage(discord.MessageCreate{Content: "followup"})
+		if err != nil {
+			return err
+		}
+		if _, err = e.UpdateFollowupMessage(followup.ID, discord.NewMessageUpdateBuilder().SetContent("edited followup").Build()); err != nil {
+			return err
+		}
+		deleted, err := e.CreateFollowupMessage(discord.MessageCreate{Content: "deleted"})
+		if err != nil {
+			return err
+		}
+		return e.DeleteFollowupMessage(deleted.ID)
+	})
+
+	rec := dispatch(t, mux, handlertest.SlashCommand("/slow"))
+
+	var callTypes []handlertest.CallType
+	for _, call := range rec.Calls() {
+		callTypes = append(callTypes, call.Type)
+	}
+	expected := []handlertest.CallType{
+		handlertest.CallTypeCreateResponse,
+		handlertest.CallTypeUpdateResponse,
+		handlertest.CallTypeCreateFollowup,
+		handlertest.CallTypeUpdateFollowup,
+		handlertest.CallTypeCreateFollowup,
+		handlertest.CallTypeDeleteFollowup,
+	}
+	if !slices.Equal(callTypes, expected) {
+		t.Errorf("expected calls %v, got %v", expected, callTypes)
+	}
+
+	if responseType, _ := rec.ResponseType(); responseType != discord.InteractionResponseTypeDeferredCreateMessage {
+		t.Errorf("expected deferred response, got %d", responseType)
+	}
+	message := rec.Message()
+	if message == nil || message.Content != "done" {
+		t.Fatalf("expected edited message, got %+v", message)
+	}
+	if message.Flags.Has(discord.MessageFlagLoading) || !message.Flags.Has(discord.MessageFlagEphemeral) {
+		t.Errorf("expected ephemeral message which is not loading, got flags %d", message.Flags)
+	}
+	if followups := rec.Followups(); len(followups) != 1 || followups[0].Content != "edited followup" {
+		t.Errorf("expected one edited followup, got %+v", followups)
+	}
+}
+
+func TestRecording_UpdateComponentMessage(t *testing.T) {
+	mux := handler.New()
+	mux.Component("/increment", func(e *handler.ComponentEvent) error {
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().SetContent("1").Build())
+	})
+
+	source := discord.Message{ID: 1, ChannelID: handlertest.DefaultChannelID, Content: "0"}
+	rec := dispatch(t, mux, handlertest.Button("/increment", handlertest.WithMessage(source)))
+	if message := rec.Message(); message == nil || message.ID != source.ID || message.Content != "1" {
+		t.Errorf("expected updated source message, got %+v", message)
+	}
+}
+
+func TestRecording_InvalidResponses(t *testing.T) {
+	data := []struct {
+		name    string
+		handler handler.CommandHandler
+		err     error
+	}{
+		{
+			name: "update message of a command",
+			handler: func(e *handler.CommandEvent) error {
+				return e.Respond(discord.InteractionResponseTypeUpdateMessage, discord.NewMessageUpdateBuilder().SetContent("update").Build())
+			},
+			err: handlertest.ErrUnknownMessage,
+		},
+		{
+			name: "edit before response",
+			handler: func(e *handler.CommandEvent) error {
+				_, err := e.UpdateInteractionResponse(discord.NewMessageUpdateBuilder().SetContent("edit").Build())
+				return err
+			},
+			err: handlertest.ErrUnknownMessage,
+		},
+		{
+			name: "followup before response",
+			handler: func(e *handler.CommandEvent) error {
+				_, err := e.CreateFollowupMessage(discord.MessageCreate{Content: "followup"})
+				return err
+			},
+			err: handler.ErrInteractionNotAcknowledged,
+		},
+		{
+			name: "unsupported request",
+			handler: func(e *handler.CommandEvent) error {
+				_, err := e.Client().Rest.GetMessage(handlertest.DefaultChannelID, 1)
+				return err
+			},
+			err: handlertest.ErrUnsupportedRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			mux := handler.New()
+			mux.Command("/test", d.handler)
+
+			rec := handlertest.New(mux).Dispatch(handlertest.SlashCommand("/test"))
+			if !errors.Is(rec.Err(), d.err) {
+				t.Errorf("expected error %v, got %v", d.err, rec.Err())
+			}
+			if calls := rec.Calls(); len(calls) != 0 {
+				t.Errorf("expected no recorded calls, got %+v", calls)
+			}
+			if rec.Response() != nil || rec.Message() != nil {
+				t.Errorf("expected no response, got %+v", rec.Response())
+			}
+		})
+	}
+}
+
+func TestRecording_AlreadyReplied(t *testing.T) {
+	mux := handler.New()
+	mux.Command("/test", func(e *handler.CommandEvent) error {
+		if err := e.CreateMessage(discord.MessageCreate{Content: "first"}); err != nil {
+			return err
+		}
+		// the event ignores further responses, so respond via rest directly
+		return e.Client().Rest.CreateInteractionResponse(e.ID(), e.Token(), discord.InteractionResponse{
+			Type: discord.InteractionResponseTypeCreateMessage,
+			Data: discord.MessageCreate{Content: "second"},
+		})
+	})
+
+	rec := handlertest.New(mux).Dispatch(handlertest.SlashCommand("/test"))
+	if !errors.Is(rec.Err(), discord.ErrInteractionAlreadyReplied) {
+		t.Errorf("expected ErrInteractionAlreadyReplied, got %v", rec.Err())
+	}
+	if calls := rec.Calls(); len(calls) != 1 {
+		t.Errorf("expected only the first response to be recorded, got %+v", calls)
+	}
+	if message := rec.Message(); message == nil || message.Content != "first" {
+		t.Errorf("expected the first message, got %+v", message)
+	}
+}
@@ -0,0 +1,493 @@
+package handlertest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/disgoorg/json/v2"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var (
+	// DefaultApplicationID is the application id of built interactions.
+	DefaultApplicationID = snowflake.ID(100000000000000001)
+	// DefaultGuildID is the guild id of built interactions, unless WithGuild or WithDM is used.
+	DefaultGuildID = snowflake.ID(100000000000000002)
+	// DefaultChannelID is the channel id of built interactions, unless WithChannel is used.
+	DefaultChannelID = snowflake.ID(100000000000000003)
+	// DefaultUser is the user of built interactions, unless WithUser is used.
+	DefaultUser = discord.User{
+		ID:       snowflake.ID(100000000000000004),
+		Username: "user",
+	}
+)
+
+var counter atomic.Uint64
+
+// newID returns a new unique snowflake.ID with the current time.
+func newID() snowflake.ID {
+	return snowflake.New(time.Now()) | snowflake.ID(counter.Add(1)&0x3FFFFF)
+}
+
+// InteractionOpt is a type alias for a function that takes an interactionConfig and is used to configure built interactions.
+type InteractionOpt func(config *interactionConfig)
+
+type interactionConfig struct {
+	User           discord.User
+	GuildID        *snowflake.ID
+	ChannelID      snowflake.ID
+	NSFW           bool
+	Locale         discord.Locale
+	GuildLocale    *discord.Locale
+	Roles          []snowflake.ID
+	Permissions    discord.Permissions
+	AppPermissions discord.Permissions
+	Message        *discord.Message
+	Resolved       discord.ResolvedData
+	Options        []option
+	Components     []discord.LayoutComponent
+}
+
+func (c *interactionConfig) apply(opts []InteractionOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithUser sets the user who triggered the interaction.
+func WithUser(user discord.User) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.User = user
+	}
+}
+
+// WithGuild sets the guild the interaction was triggered in.
+func WithGuild(guildID snowflake.ID) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.GuildID = &guildID
+	}
+}
+
+// WithDM makes the interaction a direct message interaction with the bot, which has no guild and member.
+func WithDM() InteractionOpt {
+	return func(config *interactionConfig) {
+		config.GuildID = nil
+	}
+}
+
+// WithChannel sets the channel the interaction was triggered in.
+func WithChannel(channelID snowflake.ID) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.ChannelID = channelID
+	}
+}
+
+// WithNSFW sets whether the guild channel the interaction was triggered in is age-restricted.
+func WithNSFW(nsfw bool) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.NSFW = nsfw
+	}
+}
+
+// WithLocale sets the locale of the user who triggered the interaction. It defaults to discord.LocaleEnglishUS.
+func WithLocale(locale discord.Locale) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Locale = locale
+	}
+}
+
+// WithGuildLocale sets the preferred locale of the guild the interaction was triggered in.
+func WithGuildLocale(locale discord.Locale) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.GuildLocale = &locale
+	}
+}
+
+// WithRoles sets the roles of the member who triggered the interaction.
+func WithRoles(roleIDs ...snowflake.ID) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Roles = roleIDs
+	}
+}
+
+// WithPermissions sets the permissions of the member who triggered the interaction. Members have no permissions by default.
+func WithPermissions(permissions discord.Permissions) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Permissions = permissions
+	}
+}
+
+// WithAppPermissions sets the permissions of the bot in the channel of the interaction. The bot has no permissions by default.
+func WithAppPermissions(permissions discord.Permissions) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.AppPermissions = permissions
+	}
+}
+
+// WithMessage sets the message a component interaction or a modal opened from a component was triggered on.
+// Use Recording.Message to interact with a message sent by a handler.
+func WithMessage(message discord.Message) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Message = &message
+	}
+}
+
+// WithResolved sets the resolved users, members, roles, channels and attachments of the interaction.
+func WithResolved(resolved discord.ResolvedData) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Resolved = resolved
+	}
+}
+
+type option struct {
+	Name    string                               `json:"name"`
+	Type    discord.ApplicationCommandOptionType `json:"type"`
+	Value   json.RawMessage                      `json:"value,omitempty"`
+	Options []option                             `json:"options,omitempty"`
+	Focused bool                                 `json:"focused,omitempty"`
+}
+
+// WithOption adds an option with the given name, type and value to a slash command or autocomplete interaction.
+// It panics if the value can't be marshalled to JSON.
+func WithOption(name string, optionType discord.ApplicationCommandOptionType, value any) InteractionOpt {
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("handlertest: failed to marshal value of option %q: %s", name, err))
+	}
+	return func(config *interactionConfig) {
+		config.Options = append(config.Options, option{
+			Name:  name,
+			Type:  optionType,
+			Value: rawValue,
+		})
+	}
+}
+
+// WithStringOption adds a string option to a slash command or autocomplete interaction.
+func WithStringOption(name string, value string) InteractionOpt {
+	return WithOption(name, discord.ApplicationCommandOptionTypeString, value)
+}
+
+// WithIntOption adds an integer option to a slash command or autocomplete interaction.
+func WithIntOption(name string, value int) InteractionOpt {
+	return WithOption(name, discord.ApplicationCommandOptionTypeInt, value)
+}
+
+// WithBoolOption adds a boolean option to a slash command or autocomplete interaction.
+func WithBoolOption(name string, value bool) InteractionOpt {
+	return WithOption(name, discord.ApplicationCommandOptionTypeBool, value)
+}
+
+// WithFloatOption adds a number option to a slash command or autocomplete interaction.
+func WithFloatOption(name string, value float64) InteractionOpt {
+	return WithOption(name, discord.ApplicationCommandOptionTypeFloat, value)
+}
+
+// WithUserOption adds a user option to a slash command interaction and resolves the user.
+func WithUserOption(name string, user discord.User) InteractionOpt {
+	opt := WithOption(name, discord.ApplicationCommandOptionTypeUser, user.ID)
+	return func(config *interactionConfig) {
+		opt(config)
+		if config.Resolved.Users == nil {
+			config.Resolved.Users = map[snowflake.ID]discord.User{}
+		}
+		config.Resolved.Users[user.ID] = user
+	}
+}
+
+// WithRoleOption adds a role option to a slash command interaction and resolves the role.
+func WithRoleOption(name string, role discord.Role) InteractionOpt {
+	opt := WithOption(name, discord.ApplicationCommandOptionTypeRole, role.ID)
+	return func(config *interactionConfig) {
+		opt(config)
+		if config.Resolved.Roles == nil {
+			config.Resolved.Roles = map[snowflake.ID]discord.Role{}
+		}
+		config.Resolved.Roles[role.ID] = role
+	}
+}
+
+// WithChannelOption adds a channel option to a slash command interaction. Use WithResolved to resolve the channel.
+func WithChannelOption(name string, channelID snowflake.ID) InteractionOpt {
+	return WithOption(name, discord.ApplicationCommandOptionTypeChannel, channelID)
+}
+
+// WithFocusedOption adds the string option the user is typing in to an autocomplete interaction.
+func WithFocusedOption(name string, value string) InteractionOpt {
+	opt := WithStringOption(name, value)
+	return func(config *interactionConfig) {
+		opt(config)
+		config.Options[len(config.Options)-1].Focused = true
+	}
+}
+
+// WithTextInput adds a text input with the given custom id and value to a modal submit interaction.
+func WithTextInput(customID string, value string) InteractionOpt {
+	return WithModalComponent(discord.LabelComponent{
+		Component: discord.TextInputComponent{
+			CustomID: customID,
+			Style:    discord.TextInputStyleShort,
+			Value:    value,
+		},
+	})
+}
+
+// WithModalComponent adds a submitted component, like a discord.LabelComponent with a select menu and its values, to a modal submit interaction.
+func WithModalComponent(component discord.LayoutComponent) InteractionOpt {
+	return func(config *interactionConfig) {
+		config.Components = append(config.Components, component)
+	}
+}
+
+// SlashCommand builds a slash command interaction with the given command path, like "/ping" or "/settings/language/set".
+// Options are added with WithOption, WithStringOption and the like.
+func SlashCommand(path string, opts ...InteractionOpt) discord.ApplicationCommandInteraction {
+	cfg := newInteractionConfig(opts)
+	name, options := commandOptions(path, cfg.Options)
+	return build[discord.ApplicationCommandInteraction](cfg, discord.InteractionTypeApplicationCommand, commandData{
+		ID:       newID(),
+		Name:     name,
+		Type:     discord.ApplicationCommandTypeSlash,
+		GuildID:  cfg.GuildID,
+		Options:  options,
+		Resolved: cfg.Resolved,
+	})
+}
+
+// UserCommand builds a user command interaction with the given command name on the given user.
+func UserCommand(name string, target discord.User, opts ...InteractionOpt) discord.ApplicationCommandInteraction {
+	cfg := newInteractionConfig(opts)
+	resolved := cfg.Resolved
+	resolved.Users = map[snowflake.ID]discord.User{target.ID: target}
+	return build[discord.ApplicationCommandInteraction](cfg, discord.InteractionTypeApplicationCommand, commandData{
+		ID:       newID(),
+		Name:     strings.TrimPrefix(name, "/"),
+		Type:     discord.ApplicationCommandTypeUser,
+		GuildID:  cfg.GuildID,
+		Resolved: resolved,
+		TargetID: &target.ID,
+	})
+}
+
+// MessageCommand builds a message command interaction with the given command name on the given message.
+func MessageCommand(name string, target discord.Message, opts ...InteractionOpt) discord.ApplicationCommandInteraction {
+	cfg := newInteractionConfig(opts)
+	return build[discord.ApplicationCommandInteraction](cfg, discord.InteractionTypeApplicationCommand, commandData{
+		ID:      newID(),
+		Name:    strings.TrimPrefix(name, "/"),
+		Type:    discord.ApplicationCommandTypeMessage,
+		GuildID: cfg.GuildID,
+		Resolved: discord.MessageCommandResolved{
+			Messages: map[snowflake.ID]discord.Message{target.ID: target},
+		},
+		TargetID: &target.ID,
+	})
+}
+
+// Autocomplete builds an autocomplete interaction for the slash command with the given command path.
+// The option the user is typing in is added with WithFocusedOption, the other options with WithOption, WithStringOption and the like.
+func Autocomplete(path string, opts ...InteractionOpt) discord.AutocompleteInteraction {
+	cfg := newInteractionConfig(opts)
+	name, options := commandOptions(path, cfg.Options)
+	return build[discord.AutocompleteInteraction](cfg, discord.InteractionTypeAutocomplete, commandData{
+		ID:      newID(),
+		Name:    name,
+		Type:    discord.ApplicationCommandTypeSlash,
+		GuildID: cfg.GuildID,
+		Options: options,
+	})
+}
+
+// Button builds a button interaction with the given custom id.
+func Button(customID string, opts ...InteractionOpt) discord.ComponentInteraction {
+	return component(discord.ComponentTypeButton, customID, nil, opts)
+}
+
+// StringSelectMenu builds a string select menu interaction with the given custom id and selected values.
+func StringSelectMenu(customID string, values []string, opts ...InteractionOpt) discord.ComponentInteraction {
+	return component(discord.ComponentTypeStringSelectMenu, customID, values, opts)
+}
+
+// SelectMenu builds a user, role, mentionable or channel select menu interaction with the given custom id and selected ids.
+// Use WithResolved to resolve the selected entities.
+func SelectMenu(componentType discord.ComponentType, customID string, values []snowflake.ID, opts ...InteractionOpt) discord.ComponentInteraction {
+	stringValues := make([]string, len(values))
+	for i, value := range values {
+		stringValues[i] = value.String()
+	}
+	return component(componentType, customID, stringValues, opts)
+}
+
+// Modal builds a modal submit interaction with the given custom id. The submitted values are added with WithTextInput or WithModalComponent.
+func Modal(customID string, opts ...InteractionOpt) discord.ModalSubmitInteraction {
+	cfg := newInteractionConfig(opts)
+	components := cfg.Components
+	if components == nil {
+		components = []discord.LayoutComponent{}
+	}
+	return build[discord.ModalSubmitInteraction](cfg, discord.InteractionTypeModalSubmit, modalData{
+		CustomID:   customID,
+		Components: components,
+		Resolved:   cfg.Resolved,
+	})
+}
+
+func component(componentType discord.ComponentType, customID string, values []string, opts []InteractionOpt) discord.ComponentInteraction {
+	cfg := newInteractionConfig(opts)
+	if cfg.Message == nil {
+		cfg.Message = &discord.Message{
+			ID:            newID(),
+			GuildID:       cfg.GuildID,
+			ChannelID:     cfg.ChannelID,
+			Author:        discord.User{ID: DefaultApplicationID, Username: "bot", Bot: true},
+			ApplicationID: &DefaultApplicationID,
+		}
+	}
+	return build[discord.ComponentInteraction](cfg, discord.InteractionTypeComponent, componentData{
+		CustomID:      customID,
+		ComponentType: componentType,
+		Values:        values,
+		Resolved:      cfg.Resolved,
+	})
+}
+
+func newInteractionConfig(opts []InteractionOpt) interactionConfig {
+	guildID := DefaultGuildID
+	cfg := interactionConfig{
+		User:      DefaultUser,
+		GuildID:   &guildID,
+		ChannelID: DefaultChannelID,
+		Locale:    discord.LocaleEnglishUS,
+	}
+	cfg.apply(opts)
+	return cfg
+}
+
+// commandOptions returns the command name of the given path and nests the options into its subcommand and subcommand group.
+func commandOptions(path string, options []option) (string, []option) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch len(parts) {
+	case 1:
+		return parts[0], options
+	case 2:
+		return parts[0], []option{{Name: parts[1], Type: discord.ApplicationCommandOptionTypeSubCommand, Options: options}}
+	case 3:
+		return parts[0], []option{{Name: parts[1], Type: discord.ApplicationCommandOptionTypeSubCommandGroup, Options: []option{
+			{Name: parts[2], Type: discord.ApplicationCommandOptionTypeSubCommand, Options: options},
+		}}}
+	}
+	panic(fmt.Sprintf("handlertest: invalid command path %q", path))
+}
+
+type commandData struct {
+	ID       snowflake.ID                   `json:"id"`
+	Name     string                         `json:"name"`
+	Type     discord.ApplicationCommandType `json:"type"`
+	GuildID  *snowflake.ID                  `json:"guild_id,omitempty"`
+	Options  []option                       `json:"options,omitempty"`
+	Resolved any                            `json:"resolved,omitempty"`
+	TargetID *snowflake.ID                  `json:"target_id,omitempty"`
+}
+
+type componentData struct {
+	CustomID      string                `json:"custom_id"`
+	ComponentType discord.ComponentType `json:"component_type"`
+	Values        []string              `json:"values,omitempty"`
+	Resolved      discord.ResolvedData  `json:"resolved"`
+}
+
+type modalData struct {
+	CustomID   string                    `json:"custom_id"`
+	Components []discord.LayoutComponent `json:"components"`
+	Resolved   discord.ResolvedData      `json:"resolved"`
+}
+
+type channel struct {
+	ID          snowflake.ID        `json:"id"`
+	Type        discord.ChannelType `json:"type"`
+	GuildID     *snowflake.ID       `json:"guild_id,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	NSFW        bool                `json:"nsfw,omitempty"`
+	Permissions discord.Permissions `json:"permissions"`
+}
+
+type rawInteraction struct {
+	ID             snowflake.ID                   `json:"id"`
+	Type           discord.InteractionType        `json:"type"`
+	ApplicationID  snowflake.ID                   `json:"application_id"`
+	Token          string                         `json:"token"`
+	Version        int                            `json:"version"`
+	GuildID        *snowflake.ID                  `json:"guild_id,omitempty"`
+	Channel        channel                        `json:"channel"`
+	Locale         discord.Locale                 `json:"locale"`
+	GuildLocale    *discord.Locale                `json:"guild_locale,omitempty"`
+	Member         *discord.ResolvedMember        `json:"member,omitempty"`
+	User           *discord.User                  `json:"user,omitempty"`
+	AppPermissions discord.Permissions            `json:"app_permissions"`
+	Entitlements   []discord.Entitlement          `json:"entitlements"`
+	Context        discord.InteractionContextType `json:"context"`
+	Data           any                            `json:"data"`
+	Message        *discord.Message               `json:"message,omitempty"`
+}
+
+// build builds an interaction of type T from its JSON, so it is the same as an interaction received from Discord.
+// Each interaction gets a unique id and token.
+func build[T discord.Interaction](cfg interactionConfig, interactionType discord.InteractionType, data any) T {
+	id := newID()
+	raw := rawInteraction{
+		ID:             id,
+		Type:           interactionType,
+		ApplicationID:  DefaultApplicationID,
+		Token:          "handlertest-" + strconv.FormatUint(uint64(id), 10),
+		Version:        1,
+		GuildID:        cfg.GuildID,
+		Locale:         cfg.Locale,
+		GuildLocale:    cfg.GuildLocale,
+		AppPermissions: cfg.AppPermissions,
+		Entitlements:   []discord.Entitlement{},
+		Data:           data,
+		Message:        cfg.Message,
+	}
+	if cfg.GuildID != nil {
+		raw.Channel = channel{
+			ID:          cfg.ChannelID,
+			Type:        discord.ChannelTypeGuildText,
+			GuildID:     cfg.GuildID,
+			Name:        "channel",
+			NSFW:        cfg.NSFW,
+			Permissions: cfg.Permissions,
+		}
+		raw.Member = &discord.ResolvedMember{
+			Member: discord.Member{
+				User:    cfg.User,
+				RoleIDs: cfg.Roles,
+				GuildID: *cfg.GuildID,
+			},
+			Permissions: cfg.Permissions,
+		}
+		raw.Context = discord.InteractionContextTypeGuild
+	} else {
+		raw.Channel = channel{
+			ID:          cfg.ChannelID,
+			Type:        discord.ChannelTypeDM,
+			Permissions: cfg.Permissions,
+		}
+		raw.User = &cfg.User
+		raw.Context = discord.InteractionContextTypeBotDM
+	}
+
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		panic(fmt.Sprintf("handlertest: failed to marshal interaction: %s", err))
+	}
+	interaction, err := discord.UnmarshalInteraction(rawJSON)
+	if err != nil {
+		panic(fmt.Sprintf("handlertest: failed to unmarshal interaction: %s", err))
+	}
+	return interaction.(T)
+}
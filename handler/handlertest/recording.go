@@ -0,0 +1,137 @@
+package handlertest
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// CallType is the type of a recorded Call.
+type CallType int
+
+const (
+	// CallTypeCreateResponse is a response to the interaction, like a message, a deferred message, a modal or autocomplete results.
+	CallTypeCreateResponse CallType = iota
+	// CallTypeUpdateResponse is an edit of the message of the interaction response.
+	CallTypeUpdateResponse
+	// CallTypeDeleteResponse is a deletion of the message of the interaction response.
+	CallTypeDeleteResponse
+	// CallTypeCreateFollowup is a new followup message.
+	CallTypeCreateFollowup
+	// CallTypeUpdateFollowup is an edit of a followup message.
+	CallTypeUpdateFollowup
+	// CallTypeDeleteFollowup is a deletion of a followup message.
+	CallTypeDeleteFollowup
+)
+
+func (t CallType) String() string {
+	switch t {
+	case CallTypeCreateResponse:
+		return "create response"
+	case CallTypeUpdateResponse:
+		return "update response"
+	case CallTypeDeleteResponse:
+		return "delete response"
+	case CallTypeCreateFollowup:
+		return "create followup"
+	case CallTypeUpdateFollowup:
+		return "update followup"
+	case CallTypeDeleteFollowup:
+		return "delete followup"
+	}
+	return "unknown"
+}
+
+// Call is a single request made with the token of an interaction.
+type Call struct {
+	Type CallType
+	// Response is the interaction response of CallTypeCreateResponse calls.
+	Response *discord.InteractionResponse
+	// MessageCreate is the message of CallTypeCreateFollowup calls.
+	MessageCreate *discord.MessageCreate
+	// MessageUpdate is the edit of CallTypeUpdateResponse and CallTypeUpdateFollowup calls.
+	MessageUpdate *discord.MessageUpdate
+	// MessageID is the id of the followup message of CallTypeCreateFollowup, CallTypeUpdateFollowup and CallTypeDeleteFollowup calls.
+	MessageID snowflake.ID
+}
+
+// Recording holds everything a handler did with an interaction dispatched by a Harness.
+// Requests made after Harness.Dispatch has returned, for example by handlers running in their own goroutine, are recorded as well.
+type Recording struct {
+	// Interaction is the dispatched interaction.
+	Interaction discord.Interaction
+
+	mu        sync.Mutex
+	calls     []Call
+	response  *discord.InteractionResponse
+	message   *discord.Message
+	followups []discord.Message
+	err       error
+}
+
+// Calls returns all recorded requests in the order they have been made.
+func (r *Recording) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Response returns the response to the interaction or nil if the interaction has not been responded to.
+func (r *Recording) Response() *discord.InteractionResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.response
+}
+
+// ResponseType returns the type of the response to the interaction and false if the interaction has not been responded to.
+func (r *Recording) ResponseType() (discord.InteractionResponseType, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.response == nil {
+		return 0, false
+	}
+	return r.response.Type, true
+}
+
+// Message returns the current state of the message of the interaction response with all edits applied, or nil if there is none.
+// For component interactions responded to with an update, this is the updated message of the component.
+// The message can be passed to WithMessage to build interactions with its components.
+func (r *Recording) Message() *discord.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.message == nil {
+		return nil
+	}
+	message := *r.message
+	return &message
+}
+
+// Followups returns the current state of all followup messages which have not been deleted.
+func (r *Recording) Followups() []discord.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.followups)
+}
+
+// Err returns the error the handler returned.
+func (r *Recording) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recording) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// followup returns the index of the followup message with the given id or -1. The mutex must be held.
+func (r *Recording) followup(messageID snowflake.ID) int {
+	return slices.IndexFunc(r.followups, func(message discord.Message) bool {
+		return message.ID == messageID
+	})
+}
@@ -0,0 +1,329 @@
+package handlertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var (
+	// ErrUnsupportedRequest is returned for all requests except interaction responses and followup messages, unless a rest.Rest is set with WithRest.
+	ErrUnsupportedRequest = errors.New("request is not supported by handlertest")
+	// ErrUnknownInteraction is returned for requests with the token of an interaction which has not been dispatched by the Harness.
+	ErrUnknownInteraction = errors.New("unknown interaction")
+	// ErrUnknownMessage is returned when the interaction response or a followup message does not exist.
+	ErrUnknownMessage = errors.New("unknown message")
+)
+
+var _ rest.Client = unsupportedClient{}
+
+// unsupportedClient is a rest.Client which fails all requests with ErrUnsupportedRequest.
+type unsupportedClient struct{}
+
+func (unsupportedClient) HTTPClient() *http.Client {
+	return http.DefaultClient
+}
+
+func (unsupportedClient) RateLimiter() rest.RateLimiter {
+	return nil
+}
+
+func (unsupportedClient) Close(_ context.Context) {}
+
+func (unsupportedClient) Do(endpoint *rest.CompiledEndpoint, _ any, _ any, _ ...rest.RequestOpt) error {
+	return fmt.Errorf("%w: %s %s", ErrUnsupportedRequest, endpoint.Endpoint.Method, endpoint.URL)
+}
+
+var _ rest.Interactions = (*recordingRest)(nil)
+
+// recordingRest records all interaction responses and followup messages into the Recording of the interaction and passes all other requests to the embedded rest.Rest.
+type recordingRest struct {
+	rest.Rest
+	harness *Harness
+}
+
+func (r *recordingRest) recording(interactionToken string) (*Recording, error) {
+	recording := r.harness.recording(interactionToken)
+	if recording == nil {
+		return nil, ErrUnknownInteraction
+	}
+	return recording, nil
+}
+
+func (r *recordingRest) GetInteractionResponse(_ snowflake.ID, interactionToken string, _ ...rest.RequestOpt) (*discord.Message, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if recording.message == nil {
+		return nil, ErrUnknownMessage
+	}
+	message := *recording.message
+	return &message, nil
+}
+
+func (r *recordingRest) CreateInteractionResponse(_ snowflake.ID, interactionToken string, interactionResponse discord.InteractionResponse, _ ...rest.RequestOpt) error {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	return createResponse(recording, interactionResponse)
+}
+
+func (r *recordingRest) CreateInteractionResponseWithCallback(_ snowflake.ID, interactionToken string, interactionResponse discord.InteractionResponse, _ ...rest.RequestOpt) (*discord.InteractionCallbackResponse, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if err = createResponse(recording, interactionResponse); err != nil {
+		return nil, err
+	}
+
+	callback := &discord.InteractionCallbackResponse{
+		Interaction: discord.InteractionCallback{
+			ID:   recording.Interaction.ID(),
+			Type: recording.Interaction.Type(),
+		},
+		Resource: &discord.InteractionCallbackResource{
+			Type: interactionResponse.Type,
+		},
+	}
+	if recording.message != nil {
+		message := *recording.message
+		callback.Interaction.ResponseMessageID = message.ID
+		callback.Interaction.ResponseMessageLoading = message.Flags.Has(discord.MessageFlagLoading)
+		callback.Interaction.ResponseMessageEphemeral = message.Flags.Has(discord.MessageFlagEphemeral)
+		callback.Resource.Message = &message
+	}
+	return callback, nil
+}
+
+func (r *recordingRest) UpdateInteractionResponse(_ snowflake.ID, interactionToken string, messageUpdate discord.MessageUpdate, _ ...rest.RequestOpt) (*discord.Message, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if recording.message == nil {
+		return nil, ErrUnknownMessage
+	}
+	recording.calls = append(recording.calls, Call{
+		Type:          CallTypeUpdateResponse,
+		MessageUpdate: &messageUpdate,
+	})
+	updateMessage(recording.message, messageUpdate)
+	message := *recording.message
+	return &message, nil
+}
+
+func (r *recordingRest) DeleteInteractionResponse(_ snowflake.ID, interactionToken string, _ ...rest.RequestOpt) error {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if recording.message == nil {
+		return ErrUnknownMessage
+	}
+	recording.calls = append(recording.calls, Call{
+		Type: CallTypeDeleteResponse,
+	})
+	recording.message = nil
+	return nil
+}
+
+func (r *recordingRest) GetFollowupMessage(_ snowflake.ID, interactionToken string, messageID snowflake.ID, _ ...rest.RequestOpt) (*discord.Message, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	i := recording.followup(messageID)
+	if i == -1 {
+		return nil, ErrUnknownMessage
+	}
+	message := recording.followups[i]
+	return &message, nil
+}
+
+func (r *recordingRest) CreateFollowupMessage(_ snowflake.ID, interactionToken string, messageCreate discord.MessageCreate, _ ...rest.RequestOpt) (*discord.Message, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if recording.response == nil {
+		return nil, handler.ErrInteractionNotAcknowledged
+	}
+	message := newMessage(recording.Interaction, messageCreate)
+	recording.calls = append(recording.calls, Call{
+		Type:          CallTypeCreateFollowup,
+		MessageCreate: &messageCreate,
+		MessageID:     message.ID,
+	})
+	recording.followups = append(recording.followups, message)
+	return &message, nil
+}
+
+func (r *recordingRest) UpdateFollowupMessage(_ snowflake.ID, interactionToken string, messageID snowflake.ID, messageUpdate discord.MessageUpdate, _ ...rest.RequestOpt) (*discord.Message, error) {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return nil, err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	i := recording.followup(messageID)
+	if i == -1 {
+		return nil, ErrUnknownMessage
+	}
+	recording.calls = append(recording.calls, Call{
+		Type:          CallTypeUpdateFollowup,
+		MessageUpdate: &messageUpdate,
+		MessageID:     messageID,
+	})
+	updateMessage(&recording.followups[i], messageUpdate)
+	message := recording.followups[i]
+	return &message, nil
+}
+
+func (r *recordingRest) DeleteFollowupMessage(_ snowflake.ID, interactionToken string, messageID snowflake.ID, _ ...rest.RequestOpt) error {
+	recording, err := r.recording(interactionToken)
+	if err != nil {
+		return err
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	i := recording.followup(messageID)
+	if i == -1 {
+		return ErrUnknownMessage
+	}
+	recording.calls = append(recording.calls, Call{
+		Type:      CallTypeDeleteFollowup,
+		MessageID: messageID,
+	})
+	recording.followups = append(recording.followups[:i], recording.followups[i+1:]...)
+	return nil
+}
+
+// createResponse records the response to the interaction and sets the message of the interaction response like Discord would. The mutex must be held.
+// Invalid responses are not recorded.
+func createResponse(recording *Recording, interactionResponse discord.InteractionResponse) error {
+	if interactionResponse.Type == discord.InteractionResponseTypeAcknowledge {
+		return nil
+	}
+	if recording.response != nil {
+		return discord.ErrInteractionAlreadyReplied
+	}
+
+	var message *discord.Message
+	switch interactionResponse.Type {
+	case discord.InteractionResponseTypeCreateMessage:
+		messageCreate, _ := interactionResponse.Data.(discord.MessageCreate)
+		m := newMessage(recording.Interaction, messageCreate)
+		message = &m
+
+	case discord.InteractionResponseTypeDeferredCreateMessage:
+		messageCreate, _ := interactionResponse.Data.(discord.MessageCreate)
+		m := newMessage(recording.Interaction, discord.MessageCreate{
+			Flags: messageCreate.Flags.Add(discord.MessageFlagLoading),
+		})
+		message = &m
+
+	case discord.InteractionResponseTypeUpdateMessage, discord.InteractionResponseTypeDeferredUpdateMessage:
+		if message = sourceMessage(recording.Interaction); message == nil {
+			return ErrUnknownMessage
+		}
+		if messageUpdate, ok := interactionResponse.Data.(discord.MessageUpdate); ok {
+			updateMessage(message, messageUpdate)
+		}
+	}
+
+	recording.calls = append(recording.calls, Call{
+		Type:     CallTypeCreateResponse,
+		Response: &interactionResponse,
+	})
+	recording.response = &interactionResponse
+	if message != nil {
+		recording.message = message
+	}
+	return nil
+}
+
+// sourceMessage returns a copy of the message a component interaction or a modal opened from a component was triggered on.
+func sourceMessage(interaction discord.Interaction) *discord.Message {
+	switch i := interaction.(type) {
+	case discord.ComponentInteraction:
+		message := i.Message
+		return &message
+	case discord.ModalSubmitInteraction:
+		if i.Message == nil {
+			return nil
+		}
+		message := *i.Message
+		return &message
+	}
+	return nil
+}
+
+// newMessage returns the message the given discord.MessageCreate creates as response to the given interaction.
+func newMessage(interaction discord.Interaction, messageCreate discord.MessageCreate) discord.Message {
+	applicationID := interaction.ApplicationID()
+	message := discord.Message{
+		ID:            newID(),
+		GuildID:       interaction.GuildID(),
+		Embeds:        messageCreate.Embeds,
+		Components:    messageCreate.Components,
+		CreatedAt:     time.Now(),
+		Author:        discord.User{ID: applicationID, Username: "bot", Bot: true},
+		Content:       messageCreate.Content,
+		Flags:         messageCreate.Flags,
+		WebhookID:     &applicationID,
+		ApplicationID: &applicationID,
+	}
+	if channel := interaction.Channel(); channel.MessageChannel != nil {
+		message.ChannelID = channel.ID()
+	}
+	return message
+}
+
+// updateMessage applies the given discord.MessageUpdate to the message.
+func updateMessage(message *discord.Message, messageUpdate discord.MessageUpdate) {
+	if messageUpdate.Content != nil {
+		message.Content = *messageUpdate.Content
+	}
+	if messageUpdate.Embeds != nil {
+		message.Embeds = *messageUpdate.Embeds
+	}
+	if messageUpdate.Components != nil {
+		message.Components = *messageUpdate.Components
+	}
+	// the ephemeral flag of a message can't be changed
+	if messageUpdate.Flags != nil {
+		ephemeral := message.Flags.Has(discord.MessageFlagEphemeral)
+		message.Flags = messageUpdate.Flags.Remove(discord.MessageFlagEphemeral)
+		if ephemeral {
+			message.Flags = message.Flags.Add(discord.MessageFlagEphemeral)
+		}
+	}
+	message.Flags = message.Flags.Remove(discord.MessageFlagLoading)
+	now := time.Now()
+	message.EditedTimestamp = &now
+}
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/i18n"
 	"github.com/disgoorg/disgo/rest"
 )
 
@@ -15,6 +17,28 @@ type InteractionEvent struct {
 	*events.InteractionCreate
 	Vars map[string]string
 	Ctx  context.Context
+
+	state *interactionState
+}
+
+// ResponseTimeout is the time after the creation of an interaction in which it has to be responded to.
+const ResponseTimeout = 3 * time.Second
+
+// Deadline returns the time until the interaction has to be responded to.
+// Afterwards, the interaction token can only be used for followup messages and editing a deferred response.
+func (e *InteractionEvent) Deadline() time.Time {
+	return e.ID().Time().Add(ResponseTimeout)
+}
+
+// Localizer returns the i18n.Localizer bound to the event by the middleware.Localize middleware or nil if there is none.
+func (e *InteractionEvent) Localizer() *i18n.Localizer {
+	return i18n.FromContext(e.Ctx)
+}
+
+// T translates the message with the given key for the locale of the interaction using the i18n.Localizer bound by the middleware.Localize middleware.
+// Without a Localizer, the key itself is formatted with the given args.
+func (e *InteractionEvent) T(key string, args ...any) string {
+	return e.Localizer().T(key, args...)
 }
 
 // CreateMessage responds to the interaction with a new message.
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var (
+	// ErrInteractionNotAcknowledged is returned when an interaction response is edited or a followup message is sent before the interaction has been acknowledged.
+	ErrInteractionNotAcknowledged = errors.New("interaction has not been acknowledged yet")
+	// ErrInteractionModalShown is returned when an interaction which has been responded to with a modal is responded to again, edited or followed up.
+	ErrInteractionModalShown = errors.New("interaction has been responded to with a modal")
+)
+
+// InteractionState is the response state of an interaction.
+type InteractionState int
+
+const (
+	// InteractionStateUnacknowledged means the interaction has not been responded to yet.
+	InteractionStateUnacknowledged InteractionState = iota
+	// InteractionStateDeferred means the interaction has been responded to with discord.InteractionResponseTypeDeferredCreateMessage or discord.InteractionResponseTypeDeferredUpdateMessage.
+	InteractionStateDeferred
+	// InteractionStateResponded means the interaction has been responded to with a message, a message update, autocomplete results or an activity.
+	InteractionStateResponded
+	// InteractionStateModalShown means the interaction has been responded to with a modal.
+	InteractionStateModalShown
+)
+
+func (s InteractionState) String() string {
+	switch s {
+	case InteractionStateUnacknowledged:
+		return "unacknowledged"
+	case InteractionStateDeferred:
+		return "deferred"
+	case InteractionStateResponded:
+		return "responded"
+	case InteractionStateModalShown:
+		return "modal shown"
+	}
+	return "unknown"
+}
+
+// InteractionStateError is returned when an action is not possible in the current InteractionState.
+// It wraps discord.ErrInteractionAlreadyReplied, ErrInteractionNotAcknowledged or ErrInteractionModalShown.
+type InteractionStateError struct {
+	State  InteractionState
+	Action string
+	Err    error
+}
+
+func (e *InteractionStateError) Error() string {
+	return fmt.Sprintf("cannot %s interaction in state %s: %s", e.Action, e.State, e.Err)
+}
+
+func (e *InteractionStateError) Unwrap() error {
+	return e.Err
+}
+
+// interactionState tracks the InteractionState of an interaction by wrapping its events.InteractionResponderFunc.
+type interactionState struct {
+	mu           sync.Mutex
+	state        InteractionState
+	responseType discord.InteractionResponseType
+	respond      events.InteractionResponderFunc
+}
+
+func (s *interactionState) Respond(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// acknowledging the http request does not respond to the interaction
+	if responseType == discord.InteractionResponseTypeAcknowledge {
+		return s.respond(responseType, data, opts...)
+	}
+	if err := s.check(fmt.Sprintf("respond with response type %d to", responseType), InteractionStateUnacknowledged); err != nil {
+		return err
+	}
+	if err := s.respond(responseType, data, opts...); err != nil {
+		return err
+	}
+	s.responseType = responseType
+	switch responseType {
+	case discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeDeferredUpdateMessage:
+		s.state = InteractionStateDeferred
+	case discord.InteractionResponseTypeModal:
+		s.state = InteractionStateModalShown
+	default:
+		s.state = InteractionStateResponded
+	}
+	return nil
+}
+
+// check returns an InteractionStateError if the current state is not one of the allowed states. The mutex must be held.
+func (s *interactionState) check(action string, allowed ...InteractionState) error {
+	for _, state := range allowed {
+		if s.state == state {
+			return nil
+		}
+	}
+	err := discord.ErrInteractionAlreadyReplied
+	switch s.state {
+	case InteractionStateUnacknowledged:
+		err = ErrInteractionNotAcknowledged
+	case InteractionStateModalShown:
+		err = ErrInteractionModalShown
+	}
+	return &InteractionStateError{
+		State:  s.state,
+		Action: action,
+		Err:    err,
+	}
+}
+
+// State returns the current InteractionState of the interaction.
+// Interactions which are not handled by a Mux are always reported as InteractionStateUnacknowledged.
+func (e *InteractionEvent) State() InteractionState {
+	if e.state == nil {
+		return InteractionStateUnacknowledged
+	}
+	e.state.mu.Lock()
+	defer e.state.mu.Unlock()
+	return e.state.state
+}
+
+// Reply sends the given message with the right call for the current InteractionState:
+//   - unacknowledged: the interaction is responded to with the message
+//   - deferred with DeferCreateMessage: the deferred message is edited
+//   - deferred with DeferUpdateMessage or responded: a followup message is created
+//
+// The ephemeral flag of a deferred message can't be changed anymore and is ignored.
+func (e *InteractionEvent) Reply(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error {
+	if e.state == nil {
+		return e.CreateMessage(messageCreate, opts...)
+	}
+
+	e.state.mu.Lock()
+	state, responseType := e.state.state, e.state.responseType
+	e.state.mu.Unlock()
+
+	switch state {
+	case InteractionStateUnacknowledged:
+		return e.CreateMessage(messageCreate, opts...)
+	case InteractionStateDeferred:
+		if responseType == discord.InteractionResponseTypeDeferredCreateMessage {
+			if _, err := e.UpdateInteractionResponse(MessageCreateToUpdate(messageCreate), opts...); err != nil {
+				return err
+			}
+			e.state.mu.Lock()
+			e.state.state = InteractionStateResponded
+			e.state.mu.Unlock()
+			return nil
+		}
+	}
+	_, err := e.Followup(messageCreate, opts...)
+	return err
+}
+
+// Edit edits the message of the interaction with the right call for the current InteractionState:
+//   - unacknowledged component interactions: the interaction is responded to by updating the message of the component
+//   - deferred or responded: the interaction response is edited, which is the message of the component if the interaction was deferred with DeferUpdateMessage
+func (e *InteractionEvent) Edit(messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) error {
+	if e.state == nil {
+		_, err := e.UpdateInteractionResponse(messageUpdate, opts...)
+		return err
+	}
+
+	e.state.mu.Lock()
+	state := e.state.state
+	if state == InteractionStateUnacknowledged && e.Type() == discord.InteractionTypeComponent {
+		e.state.mu.Unlock()
+		return e.UpdateMessage(messageUpdate, opts...)
+	}
+	err := e.state.check("edit", InteractionStateDeferred, InteractionStateResponded)
+	e.state.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if _, err = e.UpdateInteractionResponse(messageUpdate, opts...); err != nil {
+		return err
+	}
+	if state == InteractionStateDeferred {
+		e.state.mu.Lock()
+		e.state.state = InteractionStateResponded
+		e.state.mu.Unlock()
+	}
+	return nil
+}
+
+// Followup creates a followup message. The interaction must have been deferred or responded to with a message.
+func (e *InteractionEvent) Followup(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) (*discord.Message, error) {
+	if e.state != nil {
+		e.state.mu.Lock()
+		err := e.state.check("followup", InteractionStateDeferred, InteractionStateResponded)
+		e.state.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.CreateFollowupMessage(messageCreate, opts...)
+}
+
+// MessageCreateToUpdate converts the given discord.MessageCreate into a discord.MessageUpdate for editing a deferred message.
+// The ephemeral flag can't be changed anymore and is dropped.
+func MessageCreateToUpdate(messageCreate discord.MessageCreate) discord.MessageUpdate {
+	messageUpdate := discord.MessageUpdate{
+		Content:         &messageCreate.Content,
+		Embeds:          &messageCreate.Embeds,
+		Components:      &messageCreate.Components,
+		Files:           messageCreate.Files,
+		AllowedMentions: messageCreate.AllowedMentions,
+	}
+	if flags := messageCreate.Flags.Remove(discord.MessageFlagEphemeral); flags != discord.MessageFlagsNone {
+		messageUpdate.Flags = &flags
+	}
+	return messageUpdate
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// ErrInteractionAutoDeferred is returned by AckGuard when a response is sent which is not possible anymore after the interaction has been deferred, like a modal.
+var ErrInteractionAutoDeferred = errors.New("interaction has already been deferred automatically")
+
+// AckGuard is a middleware that defers the interaction if the handler has not responded within the given threshold after the interaction was created.
+// Discord requires a response within handler.ResponseTimeout, so the threshold should leave enough time for the defer request, 2 seconds is a good default.
+// Application commands and modals are deferred with discord.InteractionResponseTypeDeferredCreateMessage, components with discord.InteractionResponseTypeDeferredUpdateMessage.
+// If ephemeral is true, the deferred message of application commands and modals is ephemeral.
+//
+// After the interaction has been deferred, later responses of the handler are sent transparently with handler.InteractionEvent.Reply and handler.InteractionEvent.Edit,
+// so handler.InteractionEvent.State stays accurate:
+//   - CreateMessage edits the deferred message, or creates a followup message if the interaction was deferred with DeferUpdateMessage or the deferred message has already been edited
+//   - UpdateMessage edits the deferred message or the message of the component
+//   - DeferCreateMessage and DeferUpdateMessage do nothing
+//   - all other responses like Modal return ErrInteractionAutoDeferred
+//
+// Autocomplete interactions can't be deferred and are ignored.
+// Note: Use this middleware before the Go middleware, so slow handlers running in a goroutine are guarded as well.
+func AckGuard(threshold time.Duration, ephemeral bool) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			if event.Type() == discord.InteractionTypeAutocomplete {
+				return next(event)
+			}
+
+			guard := &ackGuard{
+				event:     event,
+				respond:   event.Respond,
+				ephemeral: ephemeral,
+			}
+			event.Respond = guard.Respond
+			guard.mu.Lock()
+			guard.timer = time.AfterFunc(time.Until(event.Deadline().Add(threshold-handler.ResponseTimeout)), guard.deferResponse)
+			guard.mu.Unlock()
+
+			err := next(event)
+			if err != nil {
+				guard.stop()
+			}
+			return err
+		}
+	}
+}
+
+type ackGuard struct {
+	event     *handler.InteractionEvent
+	respond   events.InteractionResponderFunc
+	ephemeral bool
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	responded bool
+	// deferred is the response type the guard deferred the interaction with or 0 if it has not been deferred by the guard
+	deferred discord.InteractionResponseType
+}
+
+func (g *ackGuard) stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.timer.Stop()
+}
+
+func (g *ackGuard) deferResponse() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.responded {
+		return
+	}
+
+	responseType := discord.InteractionResponseTypeDeferredCreateMessage
+	var data discord.InteractionResponseData
+	if g.event.Type() == discord.InteractionTypeComponent {
+		responseType = discord.InteractionResponseTypeDeferredUpdateMessage
+	} else if g.ephemeral {
+		data = discord.MessageCreate{Flags: discord.MessageFlagEphemeral}
+	}
+	if err := g.respond(responseType, data); err != nil {
+		g.event.Client().Logger.Error("failed to automatically defer interaction", slog.Any("err", err))
+		return
+	}
+	g.responded = true
+	g.deferred = responseType
+}
+
+func (g *ackGuard) Respond(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.deferred == 0 {
+		g.timer.Stop()
+		if err := g.respond(responseType, data, opts...); err != nil {
+			return err
+		}
+		g.responded = true
+		return nil
+	}
+
+	switch responseType {
+	case discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeDeferredUpdateMessage:
+		return nil
+
+	case discord.InteractionResponseTypeCreateMessage:
+		messageCreate, ok := data.(discord.MessageCreate)
+		if !ok {
+			break
+		}
+		// the interaction is deferred, so Reply edits the deferred message or creates a followup message and updates the handler.InteractionState
+		return g.event.Reply(messageCreate, opts...)
+
+	case discord.InteractionResponseTypeUpdateMessage:
+		messageUpdate, ok := data.(discord.MessageUpdate)
+		if !ok {
+			break
+		}
+		return g.event.Edit(messageUpdate, opts...)
+	}
+	return ErrInteractionAutoDeferred
+}
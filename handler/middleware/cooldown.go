@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// CooldownScope returns the key of the bucket an interaction is counted against. Interactions with an empty key are not limited.
+type CooldownScope func(event *handler.InteractionEvent) string
+
+var (
+	// CooldownScopeUser limits each user separately.
+	CooldownScopeUser CooldownScope = func(event *handler.InteractionEvent) string {
+		return "user:" + event.User().ID.String()
+	}
+	// CooldownScopeGuild limits each guild separately. Interactions outside of guilds are limited per user.
+	CooldownScopeGuild CooldownScope = func(event *handler.InteractionEvent) string {
+		if guildID := event.GuildID(); guildID != nil {
+			return "guild:" + guildID.String()
+		}
+		return CooldownScopeUser(event)
+	}
+	// CooldownScopeChannel limits each channel separately.
+	CooldownScopeChannel CooldownScope = func(event *handler.InteractionEvent) string {
+		return "channel:" + event.Channel().ID().String()
+	}
+	// CooldownScopeGlobal limits all interactions together.
+	CooldownScopeGlobal CooldownScope = func(event *handler.InteractionEvent) string {
+		return "global"
+	}
+)
+
+// CooldownBypassPermissions returns a bypass predicate for WithCooldownBypass which lets members with all the given discord.Permissions skip the cooldown.
+func CooldownBypassPermissions(permissions discord.Permissions) func(event *handler.InteractionEvent) bool {
+	return func(event *handler.InteractionEvent) bool {
+		member := event.Member()
+		return member != nil && member.Permissions.Has(permissions)
+	}
+}
+
+// CooldownStore holds the token buckets of the Cooldown middleware.
+// Implement it to share cooldowns between multiple processes, for example with redis.
+type CooldownStore interface {
+	// Take takes a token from the bucket with the given key which holds up to burst tokens and refills one token every period / burst.
+	// It returns 0 if a token was taken, otherwise how long it takes until the next token is available.
+	Take(ctx context.Context, key string, burst int, period time.Duration) (time.Duration, error)
+}
+
+// NewMemoryCooldownStore returns a CooldownStore which keeps its token buckets in memory.
+func NewMemoryCooldownStore() CooldownStore {
+	return &memoryCooldownStore{
+		buckets: map[string]*cooldownBucket{},
+	}
+}
+
+type cooldownBucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will be refilled completely with its own burst & period
+	full time.Time
+}
+
+type memoryCooldownStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*cooldownBucket
+	lastSweep time.Time
+}
+
+func (s *memoryCooldownStore) Take(_ context.Context, key string, burst int, period time.Duration) (time.Duration, error) {
+	return s.take(key, burst, period, time.Now()), nil
+}
+
+// take takes a token from the bucket with the given key at the given time and returns how long it takes until the next token is available.
+func (s *memoryCooldownStore) take(key string, burst int, period time.Duration, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval := period / time.Duration(burst)
+	s.sweep(now, period)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &cooldownBucket{tokens: float64(burst)}
+		s.buckets[key] = bucket
+	} else {
+		bucket.tokens = min(float64(burst), bucket.tokens+float64(now.Sub(bucket.updated))/float64(interval))
+	}
+	bucket.updated = now
+
+	var retryAfter time.Duration
+	if bucket.tokens < 1 {
+		retryAfter = time.Duration((1 - bucket.tokens) * float64(interval))
+	} else {
+		bucket.tokens--
+	}
+	bucket.full = now.Add(time.Duration((float64(burst) - bucket.tokens) * float64(interval)))
+	return retryAfter
+}
+
+// sweep removes buckets which have been refilled completely at most once per period, so the store does not grow forever.
+// Each bucket is checked against its own burst & period, so buckets of longer cooldowns sharing the store are kept. The mutex must be held.
+func (s *memoryCooldownStore) sweep(now time.Time, period time.Duration) {
+	if now.Sub(s.lastSweep) < period {
+		return
+	}
+	s.lastSweep = now
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+type cooldownConfig struct {
+	Scope        CooldownScope
+	Store        CooldownStore
+	Bypass       []func(event *handler.InteractionEvent) bool
+	ResponseFunc func(event *handler.InteractionEvent, retryAfter time.Duration) discord.MessageCreate
+}
+
+// CooldownOpt is a functional option for configuring the Cooldown middleware.
+type CooldownOpt func(config *cooldownConfig)
+
+// WithCooldownScope sets the CooldownScope of the Cooldown middleware. This is CooldownScopeUser by default.
+func WithCooldownScope(scope CooldownScope) CooldownOpt {
+	return func(config *cooldownConfig) {
+		config.Scope = scope
+	}
+}
+
+// WithCooldownStore sets the CooldownStore of the Cooldown middleware. By default, each Cooldown middleware has its own in-memory store.
+// The keys of a CooldownScope are not unique per command, so a shared store requires a CooldownScope which adds a prefix per command.
+func WithCooldownStore(store CooldownStore) CooldownOpt {
+	return func(config *cooldownConfig) {
+		config.Store = store
+	}
+}
+
+// WithCooldownBypass adds a predicate which lets interactions skip the cooldown if it returns true, like CooldownBypassPermissions(discord.PermissionAdministrator).
+func WithCooldownBypass(bypass func(event *handler.InteractionEvent) bool) CooldownOpt {
+	return func(config *cooldownConfig) {
+		config.Bypass = append(config.Bypass, bypass)
+	}
+}
+
+// WithCooldownResponseFunc sets the func which creates the ephemeral response to interactions which are on cooldown.
+// By default, a message with a relative timestamp of when the interaction can be used again is sent.
+func WithCooldownResponseFunc(f func(event *handler.InteractionEvent, retryAfter time.Duration) discord.MessageCreate) CooldownOpt {
+	return func(config *cooldownConfig) {
+		config.ResponseFunc = f
+	}
+}
+
+func defaultCooldownResponse(_ *handler.InteractionEvent, retryAfter time.Duration) discord.MessageCreate {
+	return discord.MessageCreate{
+		Content: fmt.Sprintf("You are on cooldown, try again %s.", discord.TimestampStyleRelative.FormatTime(time.Now().Add(retryAfter))),
+	}
+}
+
+// Cooldown is a middleware that limits interactions with a token bucket, which allows up to burst interactions per period, e.g. Cooldown(3, time.Minute).
+// Interactions which are on cooldown are responded to with an ephemeral message and the next handler is not called.
+// Autocomplete interactions are never limited, as they can't be responded to with a message.
+// Note: Attach this middleware to each command via Router.With or Router.Group, so every command has its own cooldown.
+func Cooldown(burst int, period time.Duration, opts ...CooldownOpt) handler.Middleware {
+	if burst < 1 || period <= 0 {
+		panic("cooldown burst must be at least 1 and period must be positive")
+	}
+	cfg := cooldownConfig{
+		Scope:        CooldownScopeUser,
+		ResponseFunc: defaultCooldownResponse,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryCooldownStore()
+	}
+
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			if event.Type() == discord.InteractionTypeAutocomplete {
+				return next(event)
+			}
+			for _, bypass := range cfg.Bypass {
+				if bypass(event) {
+					return next(event)
+				}
+			}
+			key := cfg.Scope(event)
+			if key == "" {
+				return next(event)
+			}
+
+			retryAfter, err := cfg.Store.Take(event.Ctx, key, burst, period)
+			if err != nil {
+				return fmt.Errorf("failed to check cooldown: %w", err)
+			}
+			if retryAfter > 0 {
+				messageCreate := cfg.ResponseFunc(event, retryAfter)
+				messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+				return event.CreateMessage(messageCreate)
+			}
+			return next(event)
+		}
+	}
+}
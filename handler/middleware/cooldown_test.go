@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+func TestMemoryCooldownStore_Take(t *testing.T) {
+	type take struct {
+		key        string
+		at         time.Duration
+		retryAfter time.Duration
+	}
+
+	// 3 tokens per 3 seconds refill one token every second
+	data := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "burst",
+			takes: []take{
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "retry after",
+			takes: []take{
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: 250 * time.Millisecond, retryAfter: 750 * time.Millisecond},
+				{at: 500 * time.Millisecond, retryAfter: 500 * time.Millisecond},
+			},
+		},
+		{
+			name: "refill",
+			takes: []take{
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: 0, retryAfter: 0},
+				{at: time.Second, retryAfter: 0},
+				{at: time.Second, retryAfter: time.Second},
+				{at: 3 * time.Second, retryAfter: 0},
+				{at: 3 * time.Second, retryAfter: 0},
+				{at: 3 * time.Second, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "refill is capped at burst",
+			takes: []take{
+				{at: 0, retryAfter: 0},
+				{at: time.Hour, retryAfter: 0},
+				{at: time.Hour, retryAfter: 0},
+				{at: time.Hour, retryAfter: 0},
+				{at: time.Hour, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "keys are separate",
+			takes: []take{
+				{key: "a", at: 0, retryAfter: 0},
+				{key: "a", at: 0, retryAfter: 0},
+				{key: "a", at: 0, retryAfter: 0},
+				{key: "b", at: 0, retryAfter: 0},
+				{key: "a", at: 0, retryAfter: time.Second},
+			},
+		},
+	}
+
+	start := time.Now()
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			s := NewMemoryCooldownStore().(*memoryCooldownStore)
+			for i, take := range d.takes {
+				if retryAfter := s.take(take.key, 3, 3*time.Second, start.Add(take.at)); retryAfter != take.retryAfter {
+					t.Errorf("take %d: expected retry after %s, got %s", i, take.retryAfter, retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryCooldownStore_Sweep(t *testing.T) {
+	s := NewMemoryCooldownStore().(*memoryCooldownStore)
+	start := time.Now()
+
+	s.take("a", 1, time.Minute, start)
+	s.take("b", 1, time.Minute, start.Add(30*time.Second))
+	if len(s.buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(s.buckets))
+	}
+
+	// a has been refilled completely, b has not
+	s.take("c", 1, time.Minute, start.Add(time.Minute))
+	if _, ok := s.buckets["a"]; ok {
+		t.Error("expected bucket a to be swept")
+	}
+	if _, ok := s.buckets["b"]; !ok {
+		t.Error("expected bucket b to be kept")
+	}
+
+	// sweeps happen at most once per period
+	s.take("d", 1, time.Minute, start.Add(100*time.Second))
+	if _, ok := s.buckets["b"]; !ok {
+		t.Error("expected bucket b to be kept until the next sweep")
+	}
+}
+
+func TestMemoryCooldownStore_SweepSharedStore(t *testing.T) {
+	s := NewMemoryCooldownStore().(*memoryCooldownStore)
+	start := time.Now()
+
+	s.take("long", 1, time.Hour, start)
+	// sweeps of shorter cooldowns keep the buckets of longer cooldowns until they are refilled completely
+	s.take("short", 1, time.Second, start.Add(2*time.Second))
+	if retryAfter := s.take("long", 1, time.Hour, start.Add(3*time.Second)); retryAfter != time.Hour-3*time.Second {
+		t.Errorf("expected retry after %s, got %s", time.Hour-3*time.Second, retryAfter)
+	}
+
+	s.take("short", 1, time.Second, start.Add(time.Hour))
+	if _, ok := s.buckets["long"]; ok {
+		t.Error("expected bucket long to be swept once it is refilled completely")
+	}
+}
+
+func TestCooldown_Bypass(t *testing.T) {
+	data := []struct {
+		name        string
+		permissions discord.Permissions
+		handled     int
+	}{
+		{name: "limited", permissions: discord.PermissionsNone, handled: 1},
+		{name: "bypassed", permissions: discord.PermissionAdministrator, handled: 3},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			var handled int
+			mux := handler.New()
+			mux.Use(Cooldown(1, time.Hour, WithCooldownBypass(CooldownBypassPermissions(discord.PermissionAdministrator))))
+			mux.Command("/test", func(e *handler.CommandEvent) error {
+				handled++
+				return e.CreateMessage(discord.MessageCreate{Content: "ok"})
+			})
+
+			h := handlertest.New(mux)
+			for range 3 {
+				rec := h.Dispatch(handlertest.SlashCommand("/test", handlertest.WithPermissions(d.permissions)))
+				if rec.Err() != nil {
+					t.Fatal(rec.Err())
+				}
+				if message := rec.Message(); message == nil {
+					t.Fatal("expected a response")
+				} else if message.Content != "ok" && !message.Flags.Has(discord.MessageFlagEphemeral) {
+					t.Errorf("expected the cooldown response to be ephemeral, got %+v", message)
+				}
+			}
+			if handled != d.handled {
+				t.Errorf("expected %d handled interactions, got %d", d.handled, handled)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"log/slog"
 
+	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
 )
@@ -20,10 +21,17 @@ func GoDefer(interactionType discord.InteractionType, updateMessage bool, epheme
 }
 
 // GoErr is a middleware that runs the next handler in a goroutine and lets you handle the error which may occur.
+// The goroutine is tracked with bot.Client.TrackWork, so bot.Client.Shutdown waits for it to finish.
+// While the bot.Client is shutting down, no new goroutines are started and bot.ErrShuttingDown is returned instead.
 func GoErr(h handler.ErrorHandler) handler.Middleware {
 	return func(next handler.Handler) handler.Handler {
 		return func(event *handler.InteractionEvent) error {
+			if event.Client().IsShuttingDown() {
+				return bot.ErrShuttingDown
+			}
+			done := event.Client().TrackWork(bot.WorkKindInteractionHandler)
 			go func() {
+				defer done()
 				if err := next(event); err != nil {
 					h(event, err)
 				}
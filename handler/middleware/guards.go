@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// GuardType is the guard which denied an interaction.
+type GuardType int
+
+const (
+	GuardTypeGuildOnly GuardType = iota
+	GuardTypeDMOnly
+	GuardTypeNSFWOnly
+	GuardTypePermissions
+	GuardTypeBotPermissions
+	GuardTypeOwnerOnly
+	GuardTypeRoles
+)
+
+// GuardDenial describes why a guard denied an interaction.
+type GuardDenial struct {
+	Type GuardType
+	// Permissions are the missing permissions of the member for GuardTypePermissions or of the bot for GuardTypeBotPermissions.
+	Permissions discord.Permissions
+	// Roles are the missing roles for GuardTypeRoles. For RequireAnyRole, these are all roles of which one is required.
+	Roles []snowflake.ID
+}
+
+// Message returns the default english message for the GuardDenial.
+func (d GuardDenial) Message() string {
+	switch d.Type {
+	case GuardTypeGuildOnly:
+		return "This can only be used in a server."
+	case GuardTypeDMOnly:
+		return "This can only be used in direct messages."
+	case GuardTypeNSFWOnly:
+		return "This can only be used in age-restricted channels."
+	case GuardTypePermissions:
+		return fmt.Sprintf("You are missing the following permissions: %s", d.Permissions)
+	case GuardTypeBotPermissions:
+		return fmt.Sprintf("I am missing the following permissions in this channel: %s", d.Permissions)
+	case GuardTypeOwnerOnly:
+		return "This can only be used by the owner of the bot."
+	case GuardTypeRoles:
+		roles := make([]string, len(d.Roles))
+		for i, roleID := range d.Roles {
+			roles[i] = discord.RoleMention(roleID)
+		}
+		return fmt.Sprintf("You are missing the following roles: %s", strings.Join(roles, ", "))
+	}
+	return "You are not allowed to do this."
+}
+
+// DenialRenderer creates the response to an interaction which has been denied by a guard. The response is always sent ephemeral.
+type DenialRenderer func(event *handler.InteractionEvent, denial GuardDenial) discord.MessageCreate
+
+// DefaultDenialRenderer responds with the GuardDenial.Message without pinging any of the mentioned roles.
+func DefaultDenialRenderer(_ *handler.InteractionEvent, denial GuardDenial) discord.MessageCreate {
+	return discord.MessageCreate{
+		Content:         denial.Message(),
+		AllowedMentions: &discord.AllowedMentions{},
+	}
+}
+
+type guardConfig struct {
+	Renderer DenialRenderer
+}
+
+// GuardOpt is a functional option for configuring a guard middleware.
+type GuardOpt func(config *guardConfig)
+
+// WithDenialRenderer sets the DenialRenderer of a guard middleware. This is DefaultDenialRenderer by default.
+func WithDenialRenderer(renderer DenialRenderer) GuardOpt {
+	return func(config *guardConfig) {
+		config.Renderer = renderer
+	}
+}
+
+// guard returns a middleware which calls the next handler if check returns nil and responds with the rendered denial otherwise.
+// Autocomplete interactions can't be responded to with a message, so denied autocomplete interactions get no choices.
+func guard(check func(event *handler.InteractionEvent) *GuardDenial, opts []GuardOpt) handler.Middleware {
+	cfg := guardConfig{
+		Renderer: DefaultDenialRenderer,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			denial := check(event)
+			if denial == nil {
+				return next(event)
+			}
+			if event.Type() == discord.InteractionTypeAutocomplete {
+				return event.AutocompleteResult(nil)
+			}
+			messageCreate := cfg.Renderer(event, *denial)
+			messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+			return event.CreateMessage(messageCreate)
+		}
+	}
+}
+
+// GuildOnly is a middleware that denies interactions outside of guilds.
+func GuildOnly(opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		if event.GuildID() == nil {
+			return &GuardDenial{Type: GuardTypeGuildOnly}
+		}
+		return nil
+	}, opts)
+}
+
+// DMOnly is a middleware that denies interactions inside of guilds.
+func DMOnly(opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		if event.GuildID() != nil {
+			return &GuardDenial{Type: GuardTypeDMOnly}
+		}
+		return nil
+	}, opts)
+}
+
+// NSFWOnly is a middleware that denies interactions outside of age-restricted channels.
+// Threads are checked by their parent channel, which requires the cache.FlagChannels to be set.
+func NSFWOnly(opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		if !isNSFWChannel(event) {
+			return &GuardDenial{Type: GuardTypeNSFWOnly}
+		}
+		return nil
+	}, opts)
+}
+
+func isNSFWChannel(event *handler.InteractionEvent) bool {
+	switch channel := event.Channel().MessageChannel.(type) {
+	case discord.GuildThread:
+		if channel.ParentID() == nil {
+			return false
+		}
+		parent, ok := event.Client().Caches.Channel(*channel.ParentID())
+		if !ok {
+			return false
+		}
+		parentChannel, ok := parent.(discord.GuildMessageChannel)
+		return ok && parentChannel.NSFW()
+	case discord.GuildMessageChannel:
+		return channel.NSFW()
+	}
+	return false
+}
+
+// RequirePermissions is a middleware that denies interactions of members which are missing any of the given discord.Permissions in the channel.
+// Interactions outside of guilds are denied with GuardTypeGuildOnly.
+func RequirePermissions(permissions discord.Permissions, opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		member := event.Member()
+		if member == nil {
+			return &GuardDenial{Type: GuardTypeGuildOnly}
+		}
+		if missing := missingPermissions(member.Permissions, permissions); missing != discord.PermissionsNone {
+			return &GuardDenial{Type: GuardTypePermissions, Permissions: missing}
+		}
+		return nil
+	}, opts)
+}
+
+// RequireBotPermissions is a middleware that denies interactions in channels in which the bot is missing any of the given discord.Permissions according to the app permissions of the interaction.
+func RequireBotPermissions(permissions discord.Permissions, opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		var appPermissions discord.Permissions
+		if event.AppPermissions() != nil {
+			appPermissions = *event.AppPermissions()
+		}
+		if missing := missingPermissions(appPermissions, permissions); missing != discord.PermissionsNone {
+			return &GuardDenial{Type: GuardTypeBotPermissions, Permissions: missing}
+		}
+		return nil
+	}, opts)
+}
+
+func missingPermissions(permissions discord.Permissions, required discord.Permissions) discord.Permissions {
+	if permissions.Has(discord.PermissionAdministrator) {
+		return discord.PermissionsNone
+	}
+	return required.Remove(permissions)
+}
+
+// OwnerOnly is a middleware that denies interactions of all users except the given owners, like the owner or team members of the application.
+func OwnerOnly(ownerIDs []snowflake.ID, opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		if !slices.Contains(ownerIDs, event.User().ID) {
+			return &GuardDenial{Type: GuardTypeOwnerOnly}
+		}
+		return nil
+	}, opts)
+}
+
+// RequireRoles is a middleware that denies interactions of members which are missing any of the given roles.
+// Interactions outside of guilds are denied with GuardTypeGuildOnly.
+func RequireRoles(roleIDs []snowflake.ID, opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		member := event.Member()
+		if member == nil {
+			return &GuardDenial{Type: GuardTypeGuildOnly}
+		}
+		var missing []snowflake.ID
+		for _, roleID := range roleIDs {
+			if !slices.Contains(member.RoleIDs, roleID) {
+				missing = append(missing, roleID)
+			}
+		}
+		if len(missing) > 0 {
+			return &GuardDenial{Type: GuardTypeRoles, Roles: missing}
+		}
+		return nil
+	}, opts)
+}
+
+// RequireAnyRole is a middleware that denies interactions of members which have none of the given roles.
+// Interactions outside of guilds are denied with GuardTypeGuildOnly.
+func RequireAnyRole(roleIDs []snowflake.ID, opts ...GuardOpt) handler.Middleware {
+	return guard(func(event *handler.InteractionEvent) *GuardDenial {
+		member := event.Member()
+		if member == nil {
+			return &GuardDenial{Type: GuardTypeGuildOnly}
+		}
+		for _, roleID := range roleIDs {
+			if slices.Contains(member.RoleIDs, roleID) {
+				return nil
+			}
+		}
+		return &GuardDenial{Type: GuardTypeRoles, Roles: roleIDs}
+	}, opts)
+}
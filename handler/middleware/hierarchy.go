@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// CanModerate is a middleware that checks whether the interacting member and the bot are allowed to moderate the target member according to the role hierarchy.
+// The target member is taken from the slash command user option with the given name or from the target of a user command.
+// If one of the checks fails, it responds with an ephemeral message explaining why and the next handler is not called.
+// Note: This middleware requires the cache.FlagGuilds and cache.FlagRoles to be set, cache.FlagMembers is needed to check the bot itself.
+// If the guild or the roles are not cached, the interaction is denied unless WithHierarchyFailOpen is used.
+// The bot itself is only checked if its member is cached.
+func CanModerate(optionName string, opts ...HierarchyOpt) handler.Middleware {
+	cfg := hierarchyConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			guildID := event.GuildID()
+			interaction, ok := event.Interaction.(discord.ApplicationCommandInteraction)
+			if guildID == nil || !ok || event.Member() == nil {
+				return next(event)
+			}
+
+			var (
+				target discord.ResolvedMember
+				found  bool
+			)
+			switch data := interaction.Data.(type) {
+			case discord.SlashCommandInteractionData:
+				target, found = data.OptMember(optionName)
+			case discord.UserCommandInteractionData:
+				target = data.TargetMember()
+				found = target.User.ID != 0
+			}
+			if !found {
+				return next(event)
+			}
+
+			actor := event.Member().Member
+			actor.GuildID = *guildID
+			target.GuildID = *guildID
+
+			caches := event.Client().Caches
+			if err := caches.CanModerate(actor, target.Member); isNotCached(err) && cfg.FailOpen {
+				return skipHierarchyCheck(event, next, err)
+			} else if err != nil {
+				return respondHierarchyError(event, "You can't moderate this member", hierarchyError(err, *guildID, actor, target.Member))
+			}
+			selfMember, ok := caches.SelfMember(*guildID)
+			if !ok {
+				return next(event)
+			}
+			if err := caches.CanModerate(selfMember, target.Member); isNotCached(err) && cfg.FailOpen {
+				return skipHierarchyCheck(event, next, err)
+			} else if err != nil {
+				return respondHierarchyError(event, "I can't moderate this member", hierarchyError(err, *guildID, selfMember, target.Member))
+			}
+			return next(event)
+		}
+	}
+}
+
+type hierarchyConfig struct {
+	FailOpen bool
+}
+
+// HierarchyOpt is a functional option for configuring the CanModerate middleware.
+type HierarchyOpt func(config *hierarchyConfig)
+
+// WithHierarchyFailOpen makes CanModerate call the next handler with a logged warning instead of denying the interaction
+// when the hierarchy can't be checked because the guild or the roles are not cached.
+// Only use this if the handler checks permissions itself, otherwise anyone can moderate anyone while the cache is cold.
+func WithHierarchyFailOpen() HierarchyOpt {
+	return func(config *hierarchyConfig) {
+		config.FailOpen = true
+	}
+}
+
+// hierarchyError wraps errors of hierarchy checks which could not be done because of missing cache entries into a *cache.HierarchyError.
+func hierarchyError(err error, guildID snowflake.ID, actor discord.Member, target discord.Member) error {
+	if !isNotCached(err) {
+		return err
+	}
+	return &cache.HierarchyError{
+		Err:      err,
+		GuildID:  guildID,
+		ActorID:  actor.User.ID,
+		TargetID: target.User.ID,
+	}
+}
+
+func isNotCached(err error) bool {
+	return errors.Is(err, cache.ErrGuildNotCached) || errors.Is(err, cache.ErrRoleNotCached)
+}
+
+// skipHierarchyCheck fails open with WithHierarchyFailOpen when the hierarchy can't be checked because the guild or the roles are not cached.
+func skipHierarchyCheck(event *handler.InteractionEvent, next handler.Handler, err error) error {
+	event.Client().Logger.Warn("skipping role hierarchy check, check the cache flags", slog.Any("err", err))
+	return next(event)
+}
+
+func respondHierarchyError(event *handler.InteractionEvent, msg string, err error) error {
+	var hierarchyErr *cache.HierarchyError
+	if errors.As(err, &hierarchyErr) {
+		err = hierarchyErr.Err
+	}
+	return event.CreateMessage(discord.MessageCreate{
+		Content: fmt.Sprintf("%s: %s", msg, err),
+		Flags:   discord.MessageFlagEphemeral,
+	})
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+func TestCanModerate(t *testing.T) {
+	const (
+		memberRoleID snowflake.ID = 1
+		modRoleID    snowflake.ID = 2
+	)
+	guildID := handlertest.DefaultGuildID
+	target := discord.User{ID: 10, Username: "target"}
+
+	newCaches := func() cache.Caches {
+		caches := cache.New(cache.WithCaches(cache.FlagGuilds, cache.FlagRoles))
+		caches.AddGuild(discord.Guild{ID: guildID, OwnerID: 99})
+		caches.AddRole(discord.Role{ID: guildID, GuildID: guildID, Position: 0})
+		caches.AddRole(discord.Role{ID: memberRoleID, GuildID: guildID, Position: 1})
+		caches.AddRole(discord.Role{ID: modRoleID, GuildID: guildID, Position: 2})
+		return caches
+	}
+
+	data := []struct {
+		name        string
+		caches      cache.Caches
+		opts        []HierarchyOpt
+		actorRoles  []snowflake.ID
+		targetRoles []snowflake.ID
+		handled     bool
+	}{
+		{name: "allow", caches: newCaches(), actorRoles: []snowflake.ID{modRoleID}, targetRoles: []snowflake.ID{memberRoleID}, handled: true},
+		{name: "deny equal", caches: newCaches(), actorRoles: []snowflake.ID{memberRoleID}, targetRoles: []snowflake.ID{memberRoleID}, handled: false},
+		{name: "deny higher", caches: newCaches(), actorRoles: []snowflake.ID{memberRoleID}, targetRoles: []snowflake.ID{modRoleID}, handled: false},
+		{name: "deny not cached", caches: cache.New(), actorRoles: []snowflake.ID{modRoleID}, targetRoles: []snowflake.ID{memberRoleID}, handled: false},
+		{name: "fail open not cached", caches: cache.New(), opts: []HierarchyOpt{WithHierarchyFailOpen()}, actorRoles: []snowflake.ID{modRoleID}, targetRoles: []snowflake.ID{memberRoleID}, handled: true},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			var handled bool
+			mux := handler.New()
+			mux.Use(CanModerate("user", d.opts...))
+			mux.Command("/ban", func(e *handler.CommandEvent) error {
+				handled = true
+				return e.CreateMessage(discord.MessageCreate{Content: "banned"})
+			})
+
+			h := handlertest.New(mux, handlertest.WithCaches(d.caches))
+			rec := h.Dispatch(handlertest.SlashCommand("/ban",
+				handlertest.WithRoles(d.actorRoles...),
+				handlertest.WithResolved(discord.ResolvedData{
+					Users: map[snowflake.ID]discord.User{target.ID: target},
+					Members: map[snowflake.ID]discord.ResolvedMember{target.ID: {
+						Member: discord.Member{User: target, RoleIDs: d.targetRoles},
+					}},
+				}),
+				handlertest.WithOption("user", discord.ApplicationCommandOptionTypeUser, target.ID),
+			))
+			if rec.Err() != nil {
+				t.Fatal(rec.Err())
+			}
+			if handled != d.handled {
+				t.Errorf("expected handled %t, got %t", d.handled, handled)
+			}
+			if message := rec.Message(); !d.handled && (message == nil || !message.Flags.Has(discord.MessageFlagEphemeral)) {
+				t.Errorf("expected an ephemeral denial, got %+v", message)
+			}
+		})
+	}
+}
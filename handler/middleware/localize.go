@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/i18n"
+)
+
+// Localize is a middleware that binds an i18n.Localizer for the locale of the interaction's user, falling back to the guild locale, to the context of the event.
+// Handlers can then translate messages with handler.InteractionEvent.T.
+func Localize(catalog *i18n.Catalog) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		return func(event *handler.InteractionEvent) error {
+			event.Ctx = i18n.NewContext(event.Ctx, catalog.InteractionLocalizer(event.Interaction))
+			return next(event)
+		}
+	}
+}
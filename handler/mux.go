@@ -71,10 +71,15 @@ func (r *Mux) OnEvent(event bot.Event) {
 		ctx = context.Background()
 	}
 
+	// copy the event, so tracking the response state does not affect other event listeners
+	interactionCreate := *e
+	state := &interactionState{respond: e.Respond}
+	interactionCreate.Respond = state.Respond
 	ie := &InteractionEvent{
-		InteractionCreate: e,
+		InteractionCreate: &interactionCreate,
 		Ctx:               ctx,
 		Vars:              make(map[string]string),
+		state:             state,
 	}
 	if err := r.Handle(path, ie); err != nil {
 		if r.errorHandler != nil {
@@ -0,0 +1,559 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/json/v2"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// SessionVar is the name of the route variable which holds the token of a session, like /cart/add/{session}.
+const SessionVar = "session"
+
+var (
+	// ErrSessionNotFound is returned when a session does not exist or has expired.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrNoSessionManager is returned by the session methods of ComponentEvent and ModalEvent if the SessionManager.Middleware is not used.
+	ErrNoSessionManager = errors.New("no session manager in context")
+	// ErrNoSessionToken is returned by the session methods of ComponentEvent and ModalEvent if the route has no SessionVar variable.
+	ErrNoSessionToken = errors.New("no session token in route variables")
+	// ErrDeleteSession can be returned by the update func of SessionManager.Update, ComponentEvent.UpdateSession and ModalEvent.UpdateSession to delete the session instead of saving it.
+	// The update is still successful. As updates are serialized, concurrent updates of the deleted session get ErrSessionNotFound, which makes one-time actions like a submit button safe against double clicks.
+	ErrDeleteSession = errors.New("delete session")
+)
+
+// Session is server-side state of a stateful interaction like a paginator or a multi-step form, which is too large for a custom id.
+// Custom ids only carry the short Token of the session.
+type Session struct {
+	Token string
+	Data  json.RawMessage
+	// ChannelID and MessageID are the message whose components are disabled when the session expires. They are 0 if no message is attached.
+	ChannelID snowflake.ID
+	MessageID snowflake.ID
+	ExpiresAt time.Time
+}
+
+// SessionStore persists Session(s). Implement it to keep sessions in a database like redis.
+// Get followed by Put is not atomic, the SessionManager only serializes updates of the same session within one process.
+type SessionStore interface {
+	// Get returns the Session with the given token or ErrSessionNotFound if it does not exist or has expired.
+	Get(ctx context.Context, token string) (Session, error)
+
+	// Put creates or replaces the given Session.
+	Put(ctx context.Context, session Session) error
+
+	// Delete deletes the Session with the given token.
+	Delete(ctx context.Context, token string) error
+
+	// PopExpired deletes and returns all Session(s) which expired before the given time.
+	PopExpired(ctx context.Context, now time.Time) ([]Session, error)
+}
+
+// NewMemorySessionStore returns a SessionStore which keeps all Session(s) in memory.
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{
+		sessions: map[string]Session{},
+	}
+}
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+}
+
+func (s *memorySessionStore) Get(_ context.Context, token string) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[token]
+	if !ok || time.Now().After(session.ExpiresAt) {
+		return Session{}, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *memorySessionStore) Put(_ context.Context, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.Token] = session
+	return nil
+}
+
+func (s *memorySessionStore) Delete(_ context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+	return nil
+}
+
+func (s *memorySessionStore) PopExpired(_ context.Context, now time.Time) ([]Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expired []Session
+	for token, session := range s.sessions {
+		if now.After(session.ExpiresAt) {
+			expired = append(expired, session)
+			delete(s.sessions, token)
+		}
+	}
+	return expired, nil
+}
+
+// NewSessionManager returns a new SessionManager configured with the given SessionManagerConfigOpt(s).
+func NewSessionManager(client *bot.Client, opts ...SessionManagerConfigOpt) *SessionManager {
+	cfg := defaultSessionManagerConfig()
+	cfg.apply(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SessionManager{
+		client: client,
+		config: cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		locks:  map[string]*sessionLock{},
+	}
+}
+
+// SessionManager creates, loads and expires Session(s).
+// Sessions expire after the TTL set via WithSessionTTL since they have been created or saved the last time.
+// After SessionManager.Start has been called, expired sessions are removed periodically and the components of their attached message are disabled.
+//
+// Add SessionManager.Middleware to the Mux to load and save sessions from ComponentEvent and ModalEvent.
+// The session token is taken from the SessionVar route variable, so routes look like /cart/add/{session}.
+//
+// Load followed by Save is an unlocked read-modify-write, so concurrent interactions with the same session, like fast clicks, can overwrite each other's changes.
+// Use Update, ComponentEvent.UpdateSession or ModalEvent.UpdateSession to modify a session.
+type SessionManager struct {
+	client *bot.Client
+	config sessionManagerConfig
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	startOnce sync.Once
+
+	locksMu sync.Mutex
+	locks   map[string]*sessionLock
+}
+
+type sessionLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Create creates a new Session with the given data marshalled as json and returns its token.
+func (m *SessionManager) Create(ctx context.Context, data any) (string, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal session data: %w", err)
+	}
+	token, err := newSessionToken()
+	if err != nil {
+		return "", err
+	}
+	return token, m.config.Store.Put(ctx, Session{
+		Token:     token,
+		Data:      rawData,
+		ExpiresAt: time.Now().Add(m.config.TTL),
+	})
+}
+
+// Load unmarshals the data of the Session with the given token into v.
+func (m *SessionManager) Load(ctx context.Context, token string, v any) error {
+	session, err := m.config.Store.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(session.Data, v)
+}
+
+// Save replaces the data of the Session with the given token and extends its expiry.
+func (m *SessionManager) Save(ctx context.Context, token string, data any) error {
+	unlock := m.lock(token)
+	defer unlock()
+	return m.save(ctx, token, data)
+}
+
+// save replaces the data of the Session with the given token and extends its expiry. The session must be locked.
+func (m *SessionManager) save(ctx context.Context, token string, data any) error {
+	session, err := m.config.Store.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+	if session.Data, err = json.Marshal(data); err != nil {
+		return fmt.Errorf("failed to marshal session data: %w", err)
+	}
+	session.ExpiresAt = time.Now().Add(m.config.TTL)
+	return m.config.Store.Put(ctx, session)
+}
+
+// Update loads the data of the Session with the given token into v, calls update and saves v again if update returns no error.
+// If update returns ErrDeleteSession, the Session is deleted instead.
+// Updates of the same session are serialized within this process, so concurrent interactions don't lose each other's changes.
+// Sessions in a SessionStore shared between multiple processes are not locked.
+func (m *SessionManager) Update(ctx context.Context, token string, v any, update func() error) error {
+	unlock := m.lock(token)
+	defer unlock()
+
+	if err := m.Load(ctx, token, v); err != nil {
+		return err
+	}
+	return m.commit(ctx, token, v, update())
+}
+
+// commit saves v as the data of the Session with the given token if the update returned no error or deletes it on ErrDeleteSession. The session must be locked.
+func (m *SessionManager) commit(ctx context.Context, token string, v any, err error) error {
+	if errors.Is(err, ErrDeleteSession) {
+		return m.config.Store.Delete(ctx, token)
+	} else if err != nil {
+		return err
+	}
+	return m.save(ctx, token, v)
+}
+
+// lock locks the session with the given token and returns the func to unlock it again.
+func (m *SessionManager) lock(token string) func() {
+	m.locksMu.Lock()
+	lock, ok := m.locks[token]
+	if !ok {
+		lock = &sessionLock{}
+		m.locks[token] = lock
+	}
+	lock.refs++
+	m.locksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		m.locksMu.Lock()
+		defer m.locksMu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(m.locks, token)
+		}
+	}
+}
+
+// Attach attaches the message with the components of the Session with the given token, so they are disabled when the session expires.
+// Messages of component interactions are attached automatically when the session is loaded from a ComponentEvent.
+// Ephemeral messages can't be edited and should not be attached.
+func (m *SessionManager) Attach(ctx context.Context, token string, channelID snowflake.ID, messageID snowflake.ID) error {
+	unlock := m.lock(token)
+	defer unlock()
+	session, err := m.config.Store.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+	session.ChannelID = channelID
+	session.MessageID = messageID
+	return m.config.Store.Put(ctx, session)
+}
+
+// attach attaches the message of the component to the Session if it is not attached yet and the message is not ephemeral.
+func (m *SessionManager) attach(ctx context.Context, session Session, message discord.Message) error {
+	if session.MessageID == message.ID || message.Flags.Has(discord.MessageFlagEphemeral) {
+		return nil
+	}
+	session.ChannelID = message.ChannelID
+	session.MessageID = message.ID
+	return m.config.Store.Put(ctx, session)
+}
+
+// Delete deletes the Session with the given token without disabling the components of its message.
+func (m *SessionManager) Delete(ctx context.Context, token string) error {
+	unlock := m.lock(token)
+	defer unlock()
+	return m.config.Store.Delete(ctx, token)
+}
+
+// Middleware returns a Middleware which makes the SessionManager available to the session methods of ComponentEvent and ModalEvent.
+func (m *SessionManager) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(event *InteractionEvent) error {
+			event.Ctx = context.WithValue(event.Ctx, sessionManagerKey{}, m)
+			return next(event)
+		}
+	}
+}
+
+// Start starts removing expired sessions every cleanup interval in the background. It does nothing if the interval is 0.
+func (m *SessionManager) Start() {
+	if m.config.CleanupInterval <= 0 {
+		return
+	}
+	m.startOnce.Do(func() {
+		m.wg.Add(1)
+		go m.loop()
+	})
+}
+
+// Close stops removing expired sessions and waits until the current cleanup is done or the context.Context is done.
+func (m *SessionManager) Close(ctx context.Context) {
+	m.cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (m *SessionManager) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.config.CleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.expire()
+		}
+	}
+}
+
+func (m *SessionManager) expire() {
+	sessions, err := m.config.Store.PopExpired(m.ctx, time.Now())
+	if err != nil {
+		m.config.Logger.Error("failed to remove expired sessions", slog.Any("err", err))
+		return
+	}
+	if !m.config.DisableOnExpiry {
+		return
+	}
+	for _, session := range sessions {
+		if session.MessageID == 0 {
+			continue
+		}
+		if err = m.disableComponents(session.ChannelID, session.MessageID); err != nil {
+			m.config.Logger.Error("failed to disable components of expired session", slog.Any("err", err), slog.String("token", session.Token), slog.String("message_id", session.MessageID.String()))
+		}
+	}
+}
+
+func (m *SessionManager) disableComponents(channelID snowflake.ID, messageID snowflake.ID) error {
+	message, err := m.client.Rest.GetMessage(channelID, messageID, rest.WithCtx(m.ctx))
+	if err != nil {
+		return err
+	}
+	components := DisableComponents(message.Components)
+	_, err = m.client.Rest.UpdateMessage(channelID, messageID, discord.MessageUpdate{Components: &components}, rest.WithCtx(m.ctx))
+	return err
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type sessionManagerKey struct{}
+
+func sessionManagerFromContext(ctx context.Context) (*SessionManager, error) {
+	if ctx == nil {
+		return nil, ErrNoSessionManager
+	}
+	m, ok := ctx.Value(sessionManagerKey{}).(*SessionManager)
+	if !ok {
+		return nil, ErrNoSessionManager
+	}
+	return m, nil
+}
+
+func sessionFromEvent(ctx context.Context, vars map[string]string) (*SessionManager, string, error) {
+	m, err := sessionManagerFromContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	token, ok := vars[SessionVar]
+	if !ok || token == "" {
+		return nil, "", ErrNoSessionToken
+	}
+	return m, token, nil
+}
+
+// SessionToken returns the token of the session of the interaction from the SessionVar route variable.
+func (e *ComponentEvent) SessionToken() string {
+	return e.Vars[SessionVar]
+}
+
+// LoadSession unmarshals the data of the session of the interaction into v and attaches the message of the component to the session.
+// LoadSession followed by SaveSession is not locked, use UpdateSession to modify the session.
+func (e *ComponentEvent) LoadSession(v any) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	unlock := m.lock(token)
+	defer unlock()
+	return e.loadSession(m, token, v)
+}
+
+// UpdateSession is like LoadSession, but calls update afterward and saves v again if update returns no error.
+// Updates of the same session are serialized, see SessionManager.Update.
+func (e *ComponentEvent) UpdateSession(v any, update func() error) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	unlock := m.lock(token)
+	defer unlock()
+
+	if err = e.loadSession(m, token, v); err != nil {
+		return err
+	}
+	return m.commit(e.Ctx, token, v, update())
+}
+
+// loadSession unmarshals the data of the session into v and attaches the message of the component. The session must be locked.
+func (e *ComponentEvent) loadSession(m *SessionManager, token string, v any) error {
+	session, err := m.config.Store.Get(e.Ctx, token)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(session.Data, v); err != nil {
+		return err
+	}
+	return m.attach(e.Ctx, session, e.Message)
+}
+
+// SaveSession replaces the data of the session of the interaction and extends its expiry.
+func (e *ComponentEvent) SaveSession(data any) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Save(e.Ctx, token, data)
+}
+
+// DeleteSession deletes the session of the interaction.
+func (e *ComponentEvent) DeleteSession() error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Delete(e.Ctx, token)
+}
+
+// SessionToken returns the token of the session of the interaction from the SessionVar route variable.
+func (e *ModalEvent) SessionToken() string {
+	return e.Vars[SessionVar]
+}
+
+// LoadSession unmarshals the data of the session of the interaction into v.
+// LoadSession followed by SaveSession is not locked, use UpdateSession to modify the session.
+func (e *ModalEvent) LoadSession(v any) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Load(e.Ctx, token, v)
+}
+
+// UpdateSession is like LoadSession, but calls update afterward and saves v again if update returns no error.
+// Updates of the same session are serialized, see SessionManager.Update.
+func (e *ModalEvent) UpdateSession(v any, update func() error) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Update(e.Ctx, token, v, update)
+}
+
+// SaveSession replaces the data of the session of the interaction and extends its expiry.
+func (e *ModalEvent) SaveSession(data any) error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Save(e.Ctx, token, data)
+}
+
+// DeleteSession deletes the session of the interaction.
+func (e *ModalEvent) DeleteSession() error {
+	m, token, err := sessionFromEvent(e.Ctx, e.Vars)
+	if err != nil {
+		return err
+	}
+	return m.Delete(e.Ctx, token)
+}
+
+// DisableComponents returns a copy of the given components with all buttons and select menus disabled, including those nested in sections and containers.
+func DisableComponents(components []discord.LayoutComponent) []discord.LayoutComponent {
+	disabled := make([]discord.LayoutComponent, len(components))
+	for i, component := range components {
+		switch c := component.(type) {
+		case discord.ActionRowComponent:
+			disabled[i] = disableActionRow(c)
+		case discord.SectionComponent:
+			disabled[i] = disableSection(c)
+		case discord.ContainerComponent:
+			subComponents := make([]discord.ContainerSubComponent, len(c.Components))
+			for j, subComponent := range c.Components {
+				switch sc := subComponent.(type) {
+				case discord.ActionRowComponent:
+					subComponents[j] = disableActionRow(sc)
+				case discord.SectionComponent:
+					subComponents[j] = disableSection(sc)
+				default:
+					subComponents[j] = subComponent
+				}
+			}
+			c.Components = subComponents
+			disabled[i] = c
+		default:
+			disabled[i] = component
+		}
+	}
+	return disabled
+}
+
+func disableActionRow(actionRow discord.ActionRowComponent) discord.ActionRowComponent {
+	components := make([]discord.InteractiveComponent, len(actionRow.Components))
+	for i, component := range actionRow.Components {
+		switch c := component.(type) {
+		case discord.ButtonComponent:
+			// link & premium buttons don't interact with the bot
+			if c.Style == discord.ButtonStyleLink || c.Style == discord.ButtonStylePremium {
+				components[i] = c
+				continue
+			}
+			components[i] = c.AsDisabled()
+		case discord.StringSelectMenuComponent:
+			components[i] = c.AsDisabled()
+		case discord.UserSelectMenuComponent:
+			components[i] = c.AsDisabled()
+		case discord.RoleSelectMenuComponent:
+			components[i] = c.AsDisabled()
+		case discord.MentionableSelectMenuComponent:
+			components[i] = c.AsDisabled()
+		case discord.ChannelSelectMenuComponent:
+			components[i] = c.AsDisabled()
+		default:
+			components[i] = component
+		}
+	}
+	actionRow.Components = components
+	return actionRow
+}
+
+func disableSection(section discord.SectionComponent) discord.SectionComponent {
+	if button, ok := section.Accessory.(discord.ButtonComponent); ok && button.Style != discord.ButtonStyleLink && button.Style != discord.ButtonStylePremium {
+		section.Accessory = button.AsDisabled()
+	}
+	return section
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultSessionManagerConfig() sessionManagerConfig {
+	return sessionManagerConfig{
+		Logger:          slog.Default(),
+		TTL:             15 * time.Minute,
+		CleanupInterval: time.Minute,
+		DisableOnExpiry: true,
+	}
+}
+
+type sessionManagerConfig struct {
+	Logger          *slog.Logger
+	Store           SessionStore
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	DisableOnExpiry bool
+}
+
+// SessionManagerConfigOpt is a functional option for configuring a SessionManager.
+type SessionManagerConfigOpt func(config *sessionManagerConfig)
+
+func (c *sessionManagerConfig) apply(opts []SessionManagerConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Store == nil {
+		c.Store = NewMemorySessionStore()
+	}
+	c.Logger = c.Logger.With(slog.String("name", "handler_sessions"))
+}
+
+// WithSessionManagerLogger overrides the default Logger in the sessionManagerConfig.
+func WithSessionManagerLogger(logger *slog.Logger) SessionManagerConfigOpt {
+	return func(config *sessionManagerConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithSessionStore sets the SessionStore of the SessionManager. By default, sessions are kept in memory.
+func WithSessionStore(store SessionStore) SessionManagerConfigOpt {
+	return func(config *sessionManagerConfig) {
+		config.Store = store
+	}
+}
+
+// WithSessionTTL sets how long a session lives after it has been created or saved the last time. This is 15 minutes by default.
+func WithSessionTTL(ttl time.Duration) SessionManagerConfigOpt {
+	return func(config *sessionManagerConfig) {
+		config.TTL = ttl
+	}
+}
+
+// WithSessionCleanupInterval sets how often expired sessions are removed after SessionManager.Start has been called. This is 1 minute by default.
+func WithSessionCleanupInterval(interval time.Duration) SessionManagerConfigOpt {
+	return func(config *sessionManagerConfig) {
+		config.CleanupInterval = interval
+	}
+}
+
+// WithSessionDisableOnExpiry sets whether the components of the message of an expired session are disabled. This is enabled by default.
+func WithSessionDisableOnExpiry(disable bool) SessionManagerConfigOpt {
+	return func(config *sessionManagerConfig) {
+		config.DisableOnExpiry = disable
+	}
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	ctx := context.Background()
+	store := handler.NewMemorySessionStore()
+	now := time.Now()
+
+	_ = store.Put(ctx, handler.Session{Token: "valid", ExpiresAt: now.Add(time.Minute)})
+	_ = store.Put(ctx, handler.Session{Token: "expired", ExpiresAt: now.Add(-time.Minute)})
+
+	if _, err := store.Get(ctx, "valid"); err != nil {
+		t.Errorf("expected valid session, got %s", err)
+	}
+	if _, err := store.Get(ctx, "expired"); !errors.Is(err, handler.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound for expired session, got %v", err)
+	}
+	if _, err := store.Get(ctx, "unknown"); !errors.Is(err, handler.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound for unknown session, got %v", err)
+	}
+
+	expired, _ := store.PopExpired(ctx, now)
+	if len(expired) != 1 || expired[0].Token != "expired" {
+		t.Fatalf("expected the expired session to be popped, got %+v", expired)
+	}
+	if expired, _ = store.PopExpired(ctx, now); len(expired) != 0 {
+		t.Errorf("expected expired sessions to be popped only once, got %+v", expired)
+	}
+
+	expired, _ = store.PopExpired(ctx, now.Add(2*time.Minute))
+	if len(expired) != 1 || expired[0].Token != "valid" {
+		t.Errorf("expected the valid session to expire, got %+v", expired)
+	}
+}
+
+func TestDisableComponents(t *testing.T) {
+	components := []discord.LayoutComponent{
+		discord.NewActionRow(
+			discord.NewPrimaryButton("Primary", "/primary"),
+			discord.NewLinkButton("Link", "https://example.com"),
+		),
+		discord.NewActionRow(discord.NewStringSelectMenu("/select", "Select", discord.NewStringSelectMenuOption("Option", "option"))),
+		discord.NewContainer(
+			discord.NewSection(discord.NewTextDisplay("Text")).WithAccessory(discord.NewDangerButton("Danger", "/danger")),
+		),
+	}
+
+	disabled := handler.DisableComponents(components)
+
+	buttons := disabled[0].(discord.ActionRowComponent).Components
+	if !buttons[0].(discord.ButtonComponent).Disabled {
+		t.Error("expected primary button to be disabled")
+	}
+	if buttons[1].(discord.ButtonComponent).Disabled {
+		t.Error("expected link button not to be disabled")
+	}
+	if !disabled[1].(discord.ActionRowComponent).Components[0].(discord.StringSelectMenuComponent).Disabled {
+		t.Error("expected select menu to be disabled")
+	}
+	section := disabled[2].(discord.ContainerComponent).Components[0].(discord.SectionComponent)
+	if !section.Accessory.(discord.ButtonComponent).Disabled {
+		t.Error("expected section accessory to be disabled")
+	}
+
+	if components[0].(discord.ActionRowComponent).Components[0].(discord.ButtonComponent).Disabled {
+		t.Error("expected the given components not to be modified")
+	}
+}
+
+type cart struct {
+	Items int `json:"items"`
+}
+
+func newSessionHarness(t *testing.T, handle func(e *handler.ComponentEvent) error) (*handlertest.Harness, *handler.SessionManager, handler.SessionStore) {
+	t.Helper()
+	mux := handler.New()
+	h := handlertest.New(mux)
+
+	store := handler.NewMemorySessionStore()
+	sessions := handler.NewSessionManager(h.Client, handler.WithSessionStore(store))
+	mux.Use(sessions.Middleware())
+	mux.Component("/cart/{session}", handle)
+	return h, sessions, store
+}
+
+func TestComponentEvent_LoadSession_Attach(t *testing.T) {
+	data := []struct {
+		name     string
+		flags    discord.MessageFlags
+		attached bool
+	}{
+		{name: "message", attached: true},
+		{name: "ephemeral message", flags: discord.MessageFlagEphemeral, attached: false},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			h, sessions, store := newSessionHarness(t, func(e *handler.ComponentEvent) error {
+				var c cart
+				return e.LoadSession(&c)
+			})
+			token, err := sessions.Create(context.Background(), cart{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			message := discord.Message{ID: 1, ChannelID: handlertest.DefaultChannelID, Flags: d.flags}
+			if rec := h.Dispatch(handlertest.Button("/cart/"+token, handlertest.WithMessage(message))); rec.Err() != nil {
+				t.Fatal(rec.Err())
+			}
+
+			session, err := store.Get(context.Background(), token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if attached := session.MessageID == message.ID && session.ChannelID == message.ChannelID; attached != d.attached {
+				t.Errorf("expected attached %t, got message %d in channel %d", d.attached, session.MessageID, session.ChannelID)
+			}
+		})
+	}
+}
+
+func TestComponentEvent_UpdateSession(t *testing.T) {
+	h, sessions, store := newSessionHarness(t, func(e *handler.ComponentEvent) error {
+		var c cart
+		return e.UpdateSession(&c, func() error {
+			// widen the window between loading and saving the session
+			time.Sleep(time.Millisecond)
+			c.Items++
+			return nil
+		})
+	})
+	token, err := sessions.Create(context.Background(), cart{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const clicks = 50
+	var wg sync.WaitGroup
+	for range clicks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.Dispatch(handlertest.Button("/cart/"+token, handlertest.WithMessage(discord.Message{ID: 1, ChannelID: handlertest.DefaultChannelID})))
+		}()
+	}
+	wg.Wait()
+
+	var c cart
+	if err = sessions.Load(context.Background(), token, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Items != clicks {
+		t.Errorf("expected %d items, got %d", clicks, c.Items)
+	}
+	if session, _ := store.Get(context.Background(), token); session.MessageID != 1 {
+		t.Errorf("expected the message to stay attached, got %d", session.MessageID)
+	}
+}
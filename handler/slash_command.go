@@ -0,0 +1,612 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// SlashCommandArgsHandler is a function that handles slash command interactions with the options decoded into the args struct T.
+type SlashCommandArgsHandler[T any] func(args T, e *CommandEvent) error
+
+// SlashCommandDefinition defines a slash command, subcommand group or subcommand together with its handler.
+// The options are derived from the fields of an args struct, so the registered command and the decoded options can't drift apart.
+//
+// The fields of the args struct support the following tags:
+//   - discord: the name of the option, followed by ",optional" to make the option optional. Defaults to the snake_case field name, "-" skips the field
+//   - description: the description of the option, defaults to the name
+//   - name_localizations and description_localizations: localizations in the format "de=Name;fr=Nom"
+//   - choices: the choices of a string, integer or number option in the format "Name=value;Other Name=other"
+//   - min and max: the min and max value of an integer or number option. Unsigned and integer fields of less than 64 bits default to the range of their type
+//   - min_length and max_length: the min and max length of a string option
+//   - channel_types: the allowed channel types of a channel option as names like "text,voice,forum" or numbers
+//   - autocomplete: "true" enables autocomplete for a string, integer or number option
+//
+// Options are required unless the field is a pointer or tagged as optional.
+// Integer values which do not fit into their field are rejected with an error instead of being truncated.
+// Supported field types are strings, integers, floats, bools, discord.User, discord.ResolvedMember, discord.ResolvedChannel, discord.Role, discord.Attachment and snowflake.ID for mentionables, as well as pointers to them.
+type SlashCommandDefinition struct {
+	Name                     string
+	NameLocalizations        map[discord.Locale]string
+	Description              string
+	DescriptionLocalizations map[discord.Locale]string
+	// DefaultMemberPermissions, IntegrationTypes, Contexts and NSFW are only used for top level commands.
+	DefaultMemberPermissions omit.Omit[*discord.Permissions]
+	IntegrationTypes         []discord.ApplicationIntegrationType
+	Contexts                 []discord.InteractionContextType
+	NSFW                     *bool
+	// Subcommands are the subcommands or subcommand groups of this command. A command with subcommands has no handler itself.
+	Subcommands []SlashCommandDefinition
+
+	options []discord.ApplicationCommandOption
+	handler SlashCommandHandler
+}
+
+// NewSlashCommand returns a new SlashCommandDefinition with the options derived from T, which must be a struct.
+// The options of an interaction are decoded into T before the handler is called.
+// It panics if T contains unsupported fields or invalid tags.
+func NewSlashCommand[T any](name string, description string, handler SlashCommandArgsHandler[T]) SlashCommandDefinition {
+	argsType := reflect.TypeFor[T]()
+	if argsType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("slash command args must be a struct, got %s", argsType))
+	}
+	fields := parseSlashCommandFields(argsType)
+
+	options := make([]discord.ApplicationCommandOption, len(fields))
+	for i, field := range fields {
+		options[i] = field.option
+	}
+	// required options must come before optional ones
+	slices.SortStableFunc(options, func(a discord.ApplicationCommandOption, b discord.ApplicationCommandOption) int {
+		return boolCompare(!isOptionRequired(a), !isOptionRequired(b))
+	})
+
+	return SlashCommandDefinition{
+		Name:        name,
+		Description: description,
+		options:     options,
+		handler: func(data discord.SlashCommandInteractionData, e *CommandEvent) error {
+			var args T
+			value := reflect.ValueOf(&args).Elem()
+			for _, field := range fields {
+				if err := field.decode(data, value.FieldByIndex(field.index)); err != nil {
+					return err
+				}
+			}
+			return handler(args, e)
+		},
+	}
+}
+
+// NewSlashCommandGroup returns a new SlashCommandDefinition which groups the given subcommands.
+// Top level groups can contain subcommands and subcommand groups, nested groups only subcommands.
+func NewSlashCommandGroup(name string, description string, subcommands ...SlashCommandDefinition) SlashCommandDefinition {
+	return SlashCommandDefinition{
+		Name:        name,
+		Description: description,
+		Subcommands: subcommands,
+	}
+}
+
+// Create returns the discord.SlashCommandCreate of this top level command.
+func (d SlashCommandDefinition) Create() discord.SlashCommandCreate {
+	options := d.options
+	if len(d.Subcommands) > 0 {
+		options = make([]discord.ApplicationCommandOption, len(d.Subcommands))
+		for i, subcommand := range d.Subcommands {
+			if len(subcommand.Subcommands) > 0 {
+				options[i] = subcommand.subcommandGroup()
+				continue
+			}
+			options[i] = subcommand.subcommand()
+		}
+	}
+	return discord.SlashCommandCreate{
+		Name:                     d.Name,
+		NameLocalizations:        d.NameLocalizations,
+		Description:              d.Description,
+		DescriptionLocalizations: d.DescriptionLocalizations,
+		Options:                  options,
+		DefaultMemberPermissions: d.DefaultMemberPermissions,
+		IntegrationTypes:         d.IntegrationTypes,
+		Contexts:                 d.Contexts,
+		NSFW:                     d.NSFW,
+	}
+}
+
+func (d SlashCommandDefinition) subcommandGroup() discord.ApplicationCommandOptionSubCommandGroup {
+	subcommands := make([]discord.ApplicationCommandOptionSubCommand, len(d.Subcommands))
+	for i, subcommand := range d.Subcommands {
+		if len(subcommand.Subcommands) > 0 {
+			panic(fmt.Sprintf("subcommand group %s can't contain the subcommand group %s", d.Name, subcommand.Name))
+		}
+		subcommands[i] = subcommand.subcommand()
+	}
+	return discord.ApplicationCommandOptionSubCommandGroup{
+		Name:                     d.Name,
+		NameLocalizations:        d.NameLocalizations,
+		Description:              d.Description,
+		DescriptionLocalizations: d.DescriptionLocalizations,
+		Options:                  subcommands,
+	}
+}
+
+func (d SlashCommandDefinition) subcommand() discord.ApplicationCommandOptionSubCommand {
+	return discord.ApplicationCommandOptionSubCommand{
+		Name:                     d.Name,
+		NameLocalizations:        d.NameLocalizations,
+		Description:              d.Description,
+		DescriptionLocalizations: d.DescriptionLocalizations,
+		Options:                  d.options,
+	}
+}
+
+// Register registers the handlers of this command on the given Router. Subcommands are routed via Router.Route.
+func (d SlashCommandDefinition) Register(r Router) {
+	if len(d.Subcommands) == 0 {
+		r.SlashCommand("/"+d.Name, d.handler)
+		return
+	}
+	r.Route("/"+d.Name, func(r Router) {
+		for _, subcommand := range d.Subcommands {
+			subcommand.Register(r)
+		}
+	})
+}
+
+// SlashCommandCreates returns the discord.ApplicationCommandCreate(s) of the given top level commands, ready to be used with SyncCommands.
+func SlashCommandCreates(definitions ...SlashCommandDefinition) []discord.ApplicationCommandCreate {
+	commands := make([]discord.ApplicationCommandCreate, len(definitions))
+	for i, definition := range definitions {
+		commands[i] = definition.Create()
+	}
+	return commands
+}
+
+var (
+	userType       = reflect.TypeFor[discord.User]()
+	memberType     = reflect.TypeFor[discord.ResolvedMember]()
+	channelType    = reflect.TypeFor[discord.ResolvedChannel]()
+	roleType       = reflect.TypeFor[discord.Role]()
+	attachmentType = reflect.TypeFor[discord.Attachment]()
+	snowflakeType  = reflect.TypeFor[snowflake.ID]()
+)
+
+var channelTypeNames = map[string]discord.ChannelType{
+	"text":                discord.ChannelTypeGuildText,
+	"voice":               discord.ChannelTypeGuildVoice,
+	"category":            discord.ChannelTypeGuildCategory,
+	"news":                discord.ChannelTypeGuildNews,
+	"announcement":        discord.ChannelTypeGuildNews,
+	"news_thread":         discord.ChannelTypeGuildNewsThread,
+	"announcement_thread": discord.ChannelTypeGuildNewsThread,
+	"public_thread":       discord.ChannelTypeGuildPublicThread,
+	"private_thread":      discord.ChannelTypeGuildPrivateThread,
+	"stage":               discord.ChannelTypeGuildStageVoice,
+	"directory":           discord.ChannelTypeGuildDirectory,
+	"forum":               discord.ChannelTypeGuildForum,
+	"media":               discord.ChannelTypeGuildMedia,
+}
+
+type slashCommandField struct {
+	index  []int
+	name   string
+	option discord.ApplicationCommandOption
+}
+
+func parseSlashCommandFields(argsType reflect.Type) []slashCommandField {
+	var fields []slashCommandField
+	for _, structField := range reflect.VisibleFields(argsType) {
+		if !structField.IsExported() || structField.Anonymous {
+			continue
+		}
+		name, flags, _ := strings.Cut(structField.Tag.Get("discord"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = toSnakeCase(structField.Name)
+		}
+		fieldType := structField.Type
+		required := flags != "optional"
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+			required = false
+		}
+
+		option, err := newSlashCommandOption(name, required, fieldType, structField.Tag)
+		if err != nil {
+			panic(fmt.Sprintf("invalid slash command option %s of field %s: %s", name, structField.Name, err))
+		}
+		fields = append(fields, slashCommandField{
+			index:  structField.Index,
+			name:   name,
+			option: option,
+		})
+	}
+	return fields
+}
+
+func newSlashCommandOption(name string, required bool, fieldType reflect.Type, tag reflect.StructTag) (discord.ApplicationCommandOption, error) {
+	description := tag.Get("description")
+	if description == "" {
+		description = name
+	}
+	nameLocalizations, err := parseLocalizations(tag.Get("name_localizations"))
+	if err != nil {
+		return nil, err
+	}
+	descriptionLocalizations, err := parseLocalizations(tag.Get("description_localizations"))
+	if err != nil {
+		return nil, err
+	}
+	autocomplete := tag.Get("autocomplete") == "true"
+
+	switch fieldType {
+	case userType, memberType:
+		return discord.ApplicationCommandOptionUser{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+		}, nil
+	case channelType:
+		var channelTypes []discord.ChannelType
+		if channelTypesTag := tag.Get("channel_types"); channelTypesTag != "" {
+			for _, channelTypeName := range strings.Split(channelTypesTag, ",") {
+				channelTypeName = strings.TrimSpace(channelTypeName)
+				if t, ok := channelTypeNames[channelTypeName]; ok {
+					channelTypes = append(channelTypes, t)
+					continue
+				}
+				t, err := strconv.Atoi(channelTypeName)
+				if err != nil {
+					return nil, fmt.Errorf("unknown channel type %q", channelTypeName)
+				}
+				channelTypes = append(channelTypes, discord.ChannelType(t))
+			}
+		}
+		return discord.ApplicationCommandOptionChannel{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+			ChannelTypes:             channelTypes,
+		}, nil
+	case roleType:
+		return discord.ApplicationCommandOptionRole{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+		}, nil
+	case attachmentType:
+		return discord.ApplicationCommandOptionAttachment{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+		}, nil
+	case snowflakeType:
+		return discord.ApplicationCommandOptionMentionable{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+		}, nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		var choices []discord.ApplicationCommandOptionChoiceString
+		err = parseChoices(tag.Get("choices"), func(choiceName string, value string) error {
+			choices = append(choices, discord.ApplicationCommandOptionChoiceString{Name: choiceName, Value: value})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		minLength, err := parseOptionalTag(tag, "min_length", strconv.Atoi)
+		if err != nil {
+			return nil, err
+		}
+		maxLength, err := parseOptionalTag(tag, "max_length", strconv.Atoi)
+		if err != nil {
+			return nil, err
+		}
+		return discord.ApplicationCommandOptionString{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+			Choices:                  choices,
+			Autocomplete:             autocomplete,
+			MinLength:                minLength,
+			MaxLength:                maxLength,
+		}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var choices []discord.ApplicationCommandOptionChoiceInt
+		err = parseChoices(tag.Get("choices"), func(choiceName string, value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			choices = append(choices, discord.ApplicationCommandOptionChoiceInt{Name: choiceName, Value: v})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		minValue, err := parseOptionalTag(tag, "min", strconv.Atoi)
+		if err != nil {
+			return nil, err
+		}
+		maxValue, err := parseOptionalTag(tag, "max", strconv.Atoi)
+		if err != nil {
+			return nil, err
+		}
+		// limit the values users can enter to the range of the field type
+		typeMin, typeMax := intRange(fieldType)
+		if minValue == nil {
+			minValue = typeMin
+		} else if typeMin != nil && *minValue < *typeMin {
+			return nil, fmt.Errorf("min %d is out of range of %s", *minValue, fieldType)
+		}
+		if maxValue == nil {
+			maxValue = typeMax
+		} else if typeMax != nil && *maxValue > *typeMax {
+			return nil, fmt.Errorf("max %d is out of range of %s", *maxValue, fieldType)
+		}
+		return discord.ApplicationCommandOptionInt{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+			Choices:                  choices,
+			Autocomplete:             autocomplete,
+			MinValue:                 minValue,
+			MaxValue:                 maxValue,
+		}, nil
+
+	case reflect.Float32, reflect.Float64:
+		parseFloat := func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		}
+		var choices []discord.ApplicationCommandOptionChoiceFloat
+		err = parseChoices(tag.Get("choices"), func(choiceName string, value string) error {
+			v, err := parseFloat(value)
+			if err != nil {
+				return err
+			}
+			choices = append(choices, discord.ApplicationCommandOptionChoiceFloat{Name: choiceName, Value: v})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		minValue, err := parseOptionalTag(tag, "min", parseFloat)
+		if err != nil {
+			return nil, err
+		}
+		maxValue, err := parseOptionalTag(tag, "max", parseFloat)
+		if err != nil {
+			return nil, err
+		}
+		return discord.ApplicationCommandOptionFloat{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+			Choices:                  choices,
+			Autocomplete:             autocomplete,
+			MinValue:                 minValue,
+			MaxValue:                 maxValue,
+		}, nil
+
+	case reflect.Bool:
+		return discord.ApplicationCommandOptionBool{
+			Name:                     name,
+			NameLocalizations:        nameLocalizations,
+			Description:              description,
+			DescriptionLocalizations: descriptionLocalizations,
+			Required:                 required,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", fieldType)
+}
+
+// decode sets the value of the option in the given field if it is present in the interaction data.
+// It returns an error if an integer does not fit into the field.
+func (f slashCommandField) decode(data discord.SlashCommandInteractionData, field reflect.Value) error {
+	fieldType := field.Type()
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	var (
+		value any
+		ok    bool
+	)
+	switch fieldType {
+	case userType:
+		value, ok = data.OptUser(f.name)
+	case memberType:
+		value, ok = data.OptMember(f.name)
+	case channelType:
+		value, ok = data.OptChannel(f.name)
+	case roleType:
+		value, ok = data.OptRole(f.name)
+	case attachmentType:
+		value, ok = data.OptAttachment(f.name)
+	case snowflakeType:
+		value, ok = data.OptSnowflake(f.name)
+	default:
+		switch fieldType.Kind() {
+		case reflect.String:
+			value, ok = data.OptString(f.name)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value, ok = data.OptInt(f.name)
+		case reflect.Float32, reflect.Float64:
+			value, ok = data.OptFloat(f.name)
+		case reflect.Bool:
+			value, ok = data.OptBool(f.name)
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	// snowflake.ID is an uint64 too
+	if fieldType == snowflakeType {
+		f.set(field, fieldType, v)
+		return nil
+	}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); reflect.Zero(fieldType).OverflowInt(n) {
+			return fmt.Errorf("value %d of option %q overflows %s", n, f.name, fieldType)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n := v.Int(); n < 0 || reflect.Zero(fieldType).OverflowUint(uint64(n)) {
+			return fmt.Errorf("value %d of option %q overflows %s", n, f.name, fieldType)
+		}
+	}
+	f.set(field, fieldType, v)
+	return nil
+}
+
+// set sets the given value converted to fieldType in the field, allocating it first if the field is a pointer.
+func (f slashCommandField) set(field reflect.Value, fieldType reflect.Type, v reflect.Value) {
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(fieldType))
+		field = field.Elem()
+	}
+	field.Set(v.Convert(fieldType))
+}
+
+// intRange returns the min and max value of the given integer type which are tighter than the range Discord allows for integer options.
+// Unsigned types have a min value of 0 and types of less than 64 bits are limited to their range.
+func intRange(t reflect.Type) (*int, *int) {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		minValue, maxValue := -(1 << (t.Bits() - 1)), 1<<(t.Bits()-1)-1
+		return &minValue, &maxValue
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		minValue, maxValue := 0, 1<<t.Bits()-1
+		return &minValue, &maxValue
+	case reflect.Uint, reflect.Uint64:
+		minValue := 0
+		return &minValue, nil
+	}
+	return nil, nil
+}
+
+func parseLocalizations(s string) (map[discord.Locale]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	localizations := map[discord.Locale]string{}
+	for _, localization := range strings.Split(s, ";") {
+		locale, value, ok := strings.Cut(localization, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid localization %q", localization)
+		}
+		localizations[discord.Locale(strings.TrimSpace(locale))] = strings.TrimSpace(value)
+	}
+	return localizations, nil
+}
+
+func parseChoices(s string, add func(name string, value string) error) error {
+	if s == "" {
+		return nil
+	}
+	for _, choice := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(choice, "=")
+		if !ok {
+			value = name
+		}
+		if err := add(strings.TrimSpace(name), strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("invalid choice %q: %w", choice, err)
+		}
+	}
+	return nil
+}
+
+func parseOptionalTag[T any](tag reflect.StructTag, key string, parse func(s string) (T, error)) (*T, error) {
+	s, ok := tag.Lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	v, err := parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", key, s, err)
+	}
+	return &v, nil
+}
+
+func isOptionRequired(option discord.ApplicationCommandOption) bool {
+	switch o := option.(type) {
+	case discord.ApplicationCommandOptionString:
+		return o.Required
+	case discord.ApplicationCommandOptionInt:
+		return o.Required
+	case discord.ApplicationCommandOptionFloat:
+		return o.Required
+	case discord.ApplicationCommandOptionBool:
+		return o.Required
+	case discord.ApplicationCommandOptionUser:
+		return o.Required
+	case discord.ApplicationCommandOptionChannel:
+		return o.Required
+	case discord.ApplicationCommandOptionRole:
+		return o.Required
+	case discord.ApplicationCommandOptionMentionable:
+		return o.Required
+	case discord.ApplicationCommandOptionAttachment:
+		return o.Required
+	}
+	return false
+}
+
+func boolCompare(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
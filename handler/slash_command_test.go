@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestNewSlashCommand_IntRange(t *testing.T) {
+	type args struct {
+		Int    int
+		Int8   int8
+		Uint   uint
+		Uint16 uint16
+		Tagged uint8 `min:"1" max:"10"`
+	}
+	definition := NewSlashCommand("test", "test", func(args args, e *CommandEvent) error {
+		return nil
+	})
+
+	data := []struct {
+		name     string
+		minValue *int
+		maxValue *int
+	}{
+		{name: "int"},
+		{name: "int8", minValue: ptr(-128), maxValue: ptr(127)},
+		{name: "uint", minValue: ptr(0)},
+		{name: "uint16", minValue: ptr(0), maxValue: ptr(65535)},
+		{name: "tagged", minValue: ptr(1), maxValue: ptr(10)},
+	}
+
+	options := map[string]discord.ApplicationCommandOptionInt{}
+	for _, option := range definition.options {
+		options[option.OptionName()] = option.(discord.ApplicationCommandOptionInt)
+	}
+	for _, d := range data {
+		option := options[d.name]
+		if !equalPtr(option.MinValue, d.minValue) || !equalPtr(option.MaxValue, d.maxValue) {
+			t.Errorf("expected range %v-%v for option %q, got %v-%v", fmtPtr(d.minValue), fmtPtr(d.maxValue), d.name, fmtPtr(option.MinValue), fmtPtr(option.MaxValue))
+		}
+	}
+}
+
+func TestNewSlashCommand_DecodeInt(t *testing.T) {
+	type args struct {
+		Int8 *int8  `discord:"int8,optional"`
+		Uint *uint  `discord:"uint,optional"`
+		U8   *uint8 `discord:"u8,optional"`
+	}
+
+	data := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{name: "int8", value: "-128", ok: true},
+		{name: "int8", value: "128", ok: false},
+		{name: "uint", value: "42", ok: true},
+		{name: "uint", value: "-1", ok: false},
+		{name: "u8", value: "255", ok: true},
+		{name: "u8", value: "256", ok: false},
+	}
+
+	for _, d := range data {
+		var called bool
+		definition := NewSlashCommand("test", "test", func(args args, e *CommandEvent) error {
+			called = true
+			return nil
+		})
+		err := definition.handler(discord.SlashCommandInteractionData{
+			Options: map[string]discord.SlashCommandOption{
+				d.name: {Name: d.name, Type: discord.ApplicationCommandOptionTypeInt, Value: json.RawMessage(d.value)},
+			},
+		}, nil)
+		if (err == nil) != d.ok || called != d.ok {
+			t.Errorf("expected ok %t for %s value %s, got err %v", d.ok, d.name, d.value, err)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalPtr(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func fmtPtr(v *int) any {
+	if v == nil {
+		return "nil"
+	}
+	return *v
+}
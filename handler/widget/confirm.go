@@ -0,0 +1,82 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// ConfirmHandler handles the decision of a Confirm prompt with the data it has been sent with.
+// The handler must respond to the interaction, for example by updating the message with the result.
+type ConfirmHandler[T any] func(e *handler.ComponentEvent, data T, confirmed bool) error
+
+type confirmState[T any] struct {
+	baseState
+	Data T `json:"data"`
+}
+
+// NewConfirm returns a new Confirm with the given route prefix, which calls the given ConfirmHandler once the owner confirms or cancels.
+func NewConfirm[T any](prefix string, sessions *handler.SessionManager, h ConfirmHandler[T], opts ...ConfigOpt) *Confirm[T] {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Confirm[T]{
+		prefix:   prefix,
+		sessions: sessions,
+		handler:  h,
+		config:   cfg,
+	}
+}
+
+// Confirm is a prompt with a confirm and a cancel button.
+type Confirm[T any] struct {
+	prefix   string
+	sessions *handler.SessionManager
+	handler  ConfirmHandler[T]
+	config   config
+}
+
+// Register registers the route of the Confirm to the given handler.Router.
+func (c *Confirm[T]) Register(r handler.Router) {
+	r.Component(pattern(c.prefix), c.handle)
+}
+
+// Send responds to the given interaction with the given prompt. The data is passed to the ConfirmHandler. The user of the interaction owns the prompt.
+func (c *Confirm[T]) Send(ctx context.Context, e Responder, prompt string, data T) error {
+	state := confirmState[T]{
+		baseState: baseState{OwnerID: e.User().ID},
+		Data:      data,
+	}
+	return send(ctx, c.sessions, e, c.config, state, func(token string) discord.MessageCreate {
+		return render(c.config, []discord.ContainerSubComponent{discord.NewTextDisplay(prompt)}, prompt, nil,
+			discord.NewActionRow(
+				discord.NewSuccessButton(c.config.ConfirmLabel, customID(c.prefix, token, "confirm")),
+				discord.NewDangerButton(c.config.CancelLabel, customID(c.prefix, token, "cancel")),
+			),
+		)
+	})
+}
+
+func (c *Confirm[T]) handle(e *handler.ComponentEvent) error {
+	var (
+		state     confirmState[T]
+		confirmed bool
+	)
+	ok, err := updateState(e, c.sessions, c.config, &state, func() error {
+		switch e.Vars["action"] {
+		case "confirm":
+			confirmed = true
+		case "cancel":
+		default:
+			return fmt.Errorf("unknown confirm action %q", e.Vars["action"])
+		}
+		// the prompt can only be answered once
+		return handler.ErrDeleteSession
+	})
+	if !ok || err != nil {
+		return err
+	}
+	return c.handler(e, state.Data, confirmed)
+}
@@ -0,0 +1,148 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// maxSelectOptions is the maximum number of options of a select menu.
+const maxSelectOptions = 25
+
+// Page is the content of a single page of a Paginator.
+type Page struct {
+	// Components are the content of the page with components v2.
+	Components []discord.ContainerSubComponent
+	// Content and Embeds are the content of the page without components v2.
+	Content string
+	Embeds  []discord.Embed
+}
+
+// PageFunc renders the page with the given zero-based index of the given data.
+type PageFunc[T any] func(data T, page int) Page
+
+type paginatorState[T any] struct {
+	baseState
+	Data T   `json:"data"`
+	Page int `json:"page"`
+}
+
+// NewPaginator returns a new Paginator with the given route prefix, which renders the pages of data T with the given PageFunc.
+// pageCount returns the number of pages of the data.
+func NewPaginator[T any](prefix string, sessions *handler.SessionManager, pageCount func(data T) int, render PageFunc[T], opts ...ConfigOpt) *Paginator[T] {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Paginator[T]{
+		prefix:    prefix,
+		sessions:  sessions,
+		pageCount: pageCount,
+		render:    render,
+		config:    cfg,
+	}
+}
+
+// Paginator shows data page by page with buttons to go to the first, previous, next and last page, a select menu to jump to a page and a button to close it.
+type Paginator[T any] struct {
+	prefix    string
+	sessions  *handler.SessionManager
+	pageCount func(data T) int
+	render    PageFunc[T]
+	config    config
+}
+
+// Register registers the route of the Paginator to the given handler.Router.
+func (p *Paginator[T]) Register(r handler.Router) {
+	r.Component(pattern(p.prefix), p.handle)
+}
+
+// Send responds to the given interaction with the first page of the given data. The user of the interaction owns the Paginator.
+func (p *Paginator[T]) Send(ctx context.Context, e Responder, data T) error {
+	state := paginatorState[T]{
+		baseState: baseState{OwnerID: e.User().ID},
+		Data:      data,
+	}
+	return send(ctx, p.sessions, e, p.config, state, func(token string) discord.MessageCreate {
+		return p.message(token, state)
+	})
+}
+
+func (p *Paginator[T]) handle(e *handler.ComponentEvent) error {
+	var state paginatorState[T]
+	action := e.Vars["action"]
+	ok, err := updateState(e, p.sessions, p.config, &state, func() error {
+		pages := max(p.pageCount(state.Data), 1)
+		switch action {
+		case "first":
+			state.Page = 0
+		case "prev":
+			state.Page--
+		case "next":
+			state.Page++
+		case "last":
+			state.Page = pages - 1
+		case "select":
+			data, ok := e.Data.(discord.StringSelectMenuInteractionData)
+			if !ok || len(data.Values) == 0 {
+				return fmt.Errorf("invalid paginator select interaction")
+			}
+			page, err := strconv.Atoi(data.Values[0])
+			if err != nil {
+				return fmt.Errorf("invalid paginator page: %w", err)
+			}
+			state.Page = page
+		case "close":
+			return handler.ErrDeleteSession
+		default:
+			return fmt.Errorf("unknown paginator action %q", action)
+		}
+		state.Page = min(max(state.Page, 0), pages-1)
+		return nil
+	})
+	if !ok || err != nil {
+		return err
+	}
+
+	if action == "close" {
+		return disable(e)
+	}
+	return e.UpdateMessage(toMessageUpdate(p.config, p.message(e.Vars[handler.SessionVar], state)))
+}
+
+func (p *Paginator[T]) message(token string, state paginatorState[T]) discord.MessageCreate {
+	pages := max(p.pageCount(state.Data), 1)
+	page := p.render(state.Data, state.Page)
+
+	controls := []discord.ActionRowComponent{
+		discord.NewActionRow(
+			discord.NewSecondaryButton("⏮", customID(p.prefix, token, "first")).WithDisabled(state.Page == 0),
+			discord.NewSecondaryButton("◀", customID(p.prefix, token, "prev")).WithDisabled(state.Page == 0),
+			discord.NewSecondaryButton(fmt.Sprintf("%d/%d", state.Page+1, pages), customID(p.prefix, token, "page")).AsDisabled(),
+			discord.NewSecondaryButton("▶", customID(p.prefix, token, "next")).WithDisabled(state.Page >= pages-1),
+			discord.NewSecondaryButton("⏭", customID(p.prefix, token, "last")).WithDisabled(state.Page >= pages-1),
+		),
+	}
+	if pages > 1 {
+		controls = append(controls, discord.NewActionRow(
+			discord.NewStringSelectMenu(customID(p.prefix, token, "select"), "Jump to page", pageOptions(state.Page, pages)...),
+		))
+	}
+	controls = append(controls, discord.NewActionRow(
+		discord.NewDangerButton(p.config.CloseLabel, customID(p.prefix, token, "close")),
+	))
+	return render(p.config, page.Components, page.Content, page.Embeds, controls...)
+}
+
+// pageOptions returns the select menu options of up to maxSelectOptions pages around the current page.
+func pageOptions(current int, pages int) []discord.StringSelectMenuOption {
+	start := min(max(current-maxSelectOptions/2, 0), max(pages-maxSelectOptions, 0))
+	end := min(start+maxSelectOptions, pages)
+	options := make([]discord.StringSelectMenuOption, 0, end-start)
+	for page := start; page < end; page++ {
+		options = append(options, discord.NewStringSelectMenuOption(fmt.Sprintf("Page %d", page+1), strconv.Itoa(page)).WithDefault(page == current))
+	}
+	return options
+}
@@ -0,0 +1,145 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+// PickerHandler handles the selection of a Picker with the data it has been sent with.
+// submitted is false if the owner canceled the Picker. The handler must respond to the interaction, for example by updating the message with the result.
+type PickerHandler[T any] func(e *handler.ComponentEvent, data T, values []string, submitted bool) error
+
+// PickerPrompt is the content of a Picker.
+type PickerPrompt struct {
+	Content     string                           `json:"content"`
+	Placeholder string                           `json:"placeholder"`
+	Options     []discord.StringSelectMenuOption `json:"options"`
+	// MinValues and MaxValues are the minimum and maximum number of options which can be picked. MaxValues defaults to the number of options.
+	MinValues int `json:"min_values"`
+	MaxValues int `json:"max_values"`
+}
+
+type pickerState[T any] struct {
+	baseState
+	Data     T            `json:"data"`
+	Prompt   PickerPrompt `json:"prompt"`
+	Selected []string     `json:"selected"`
+}
+
+// NewPicker returns a new Picker with the given route prefix, which calls the given PickerHandler once the owner submits or cancels.
+func NewPicker[T any](prefix string, sessions *handler.SessionManager, h PickerHandler[T], opts ...ConfigOpt) *Picker[T] {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Picker[T]{
+		prefix:   prefix,
+		sessions: sessions,
+		handler:  h,
+		config:   cfg,
+	}
+}
+
+// Picker is a multi-select menu with a submit and a cancel button. The picked options are kept until the owner submits them.
+type Picker[T any] struct {
+	prefix   string
+	sessions *handler.SessionManager
+	handler  PickerHandler[T]
+	config   config
+}
+
+// Register registers the route of the Picker to the given handler.Router.
+func (p *Picker[T]) Register(r handler.Router) {
+	r.Component(pattern(p.prefix), p.handle)
+}
+
+// Send responds to the given interaction with the given PickerPrompt. The data is passed to the PickerHandler. The user of the interaction owns the Picker.
+// Options marked as default are picked initially.
+// It returns an error if the number of options, MinValues, MaxValues or the number of default options are not valid for a select menu.
+func (p *Picker[T]) Send(ctx context.Context, e Responder, prompt PickerPrompt, data T) error {
+	if len(prompt.Options) == 0 || len(prompt.Options) > maxSelectOptions {
+		return fmt.Errorf("picker must have between 1 and %d options, got %d", maxSelectOptions, len(prompt.Options))
+	}
+	if prompt.MaxValues <= 0 {
+		prompt.MaxValues = len(prompt.Options)
+	}
+	if prompt.MaxValues > len(prompt.Options) {
+		return fmt.Errorf("picker max values %d exceed the number of options %d", prompt.MaxValues, len(prompt.Options))
+	}
+	if prompt.MinValues < 0 || prompt.MinValues > prompt.MaxValues {
+		return fmt.Errorf("picker min values must be between 0 and max values %d, got %d", prompt.MaxValues, prompt.MinValues)
+	}
+	state := pickerState[T]{
+		baseState: baseState{OwnerID: e.User().ID},
+		Data:      data,
+		Prompt:    prompt,
+	}
+	for _, option := range prompt.Options {
+		if option.Default {
+			state.Selected = append(state.Selected, option.Value)
+		}
+	}
+	if len(state.Selected) > prompt.MaxValues {
+		return fmt.Errorf("picker has %d default options, which exceeds max values %d", len(state.Selected), prompt.MaxValues)
+	}
+	return send(ctx, p.sessions, e, p.config, state, func(token string) discord.MessageCreate {
+		return p.message(token, state)
+	})
+}
+
+func (p *Picker[T]) handle(e *handler.ComponentEvent) error {
+	var state pickerState[T]
+	action := e.Vars["action"]
+	ok, err := updateState(e, p.sessions, p.config, &state, func() error {
+		switch action {
+		case "select":
+			data, ok := e.Data.(discord.StringSelectMenuInteractionData)
+			if !ok {
+				return fmt.Errorf("invalid picker select interaction")
+			}
+			state.Selected = data.Values
+			return nil
+		case "submit", "cancel":
+			// the picker can only be submitted once
+			return handler.ErrDeleteSession
+		}
+		return fmt.Errorf("unknown picker action %q", action)
+	})
+	if !ok || err != nil {
+		return err
+	}
+
+	switch action {
+	case "select":
+		return e.UpdateMessage(toMessageUpdate(p.config, p.message(e.Vars[handler.SessionVar], state)))
+	case "cancel":
+		return p.handler(e, state.Data, nil, false)
+	}
+	return p.handler(e, state.Data, state.Selected, true)
+}
+
+func (p *Picker[T]) message(token string, state pickerState[T]) discord.MessageCreate {
+	options := make([]discord.StringSelectMenuOption, len(state.Prompt.Options))
+	for i, option := range state.Prompt.Options {
+		options[i] = option.WithDefault(slices.Contains(state.Selected, option.Value))
+	}
+
+	var body []discord.ContainerSubComponent
+	if state.Prompt.Content != "" {
+		body = append(body, discord.NewTextDisplay(state.Prompt.Content))
+	}
+	return render(p.config, body, state.Prompt.Content, nil,
+		discord.NewActionRow(
+			discord.NewStringSelectMenu(customID(p.prefix, token, "select"), state.Prompt.Placeholder, options...).
+				WithMinValues(state.Prompt.MinValues).
+				WithMaxValues(state.Prompt.MaxValues),
+		),
+		discord.NewActionRow(
+			discord.NewSuccessButton(p.config.SubmitLabel, customID(p.prefix, token, "submit")).WithDisabled(len(state.Selected) < state.Prompt.MinValues),
+			discord.NewDangerButton(p.config.CancelLabel, customID(p.prefix, token, "cancel")),
+		),
+	)
+}
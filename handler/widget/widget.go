@@ -0,0 +1,147 @@
+// Package widget provides reusable interactive messages for the handler package: a Paginator, a Confirm prompt, a multi-select Picker and a multi-step modal Wizard.
+//
+// Widgets keep their state in a handler.Session, so their custom ids only carry the session token and the action, like /pages/{session}/next.
+// Each widget is registered once to a handler.Router with Register and sent with Send, or Start for a Wizard, as response to an interaction.
+// Only the user who triggered the interaction can use a widget, other users get an ephemeral message.
+// When the session of a widget expires, its components are disabled by the handler.SessionManager, so the SessionManager has to be started.
+package widget
+
+import (
+	"context"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// Responder is an interaction event a widget can be sent as response to, like *handler.CommandEvent or *handler.ComponentEvent.
+type Responder interface {
+	User() discord.User
+	CreateMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error
+	GetInteractionResponse(opts ...rest.RequestOpt) (*discord.Message, error)
+}
+
+// errNotOwner is returned by the update func of updateState if the user of the interaction does not own the widget.
+var errNotOwner = errors.New("user does not own the widget")
+
+// ownedState is the state of a widget which can only be used by its owner.
+type ownedState interface {
+	owner() snowflake.ID
+}
+
+type baseState struct {
+	OwnerID snowflake.ID `json:"owner_id"`
+}
+
+func (s baseState) owner() snowflake.ID {
+	return s.OwnerID
+}
+
+// send creates a session with the given state, responds with the rendered message and attaches the message to the session.
+func send(ctx context.Context, sessions *handler.SessionManager, e Responder, cfg config, state any, render func(token string) discord.MessageCreate) error {
+	token, err := sessions.Create(ctx, state)
+	if err != nil {
+		return err
+	}
+
+	messageCreate := render(token)
+	if cfg.Ephemeral {
+		messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+	}
+	if err = e.CreateMessage(messageCreate, rest.WithCtx(ctx)); err != nil {
+		_ = sessions.Delete(ctx, token)
+		return err
+	}
+	// ephemeral messages can't be edited with the bot token, so there is nothing to disable on expiry
+	if cfg.Ephemeral {
+		return nil
+	}
+	message, err := e.GetInteractionResponse(rest.WithCtx(ctx))
+	if err != nil {
+		return err
+	}
+	return sessions.Attach(ctx, token, message.ChannelID, message.ID)
+}
+
+// updateState loads the state of the widget of the component interaction and modifies it with the given func under the lock of its session, see handler.SessionManager.Update.
+// The func returns handler.ErrDeleteSession to finish the widget, so a widget can only be finished once even if it is clicked multiple times.
+// If the session has expired or the widget has already been finished, the components of the message are disabled. If the user is not the owner, the NotOwnerMessage is sent.
+// In both cases, the interaction has been responded to and false is returned.
+func updateState(e *handler.ComponentEvent, sessions *handler.SessionManager, cfg config, state ownedState, update func() error) (bool, error) {
+	err := sessions.Update(e.Ctx, e.Vars[handler.SessionVar], state, func() error {
+		if e.User().ID != state.owner() {
+			return errNotOwner
+		}
+		return update()
+	})
+	if errors.Is(err, handler.ErrSessionNotFound) {
+		return false, disable(e)
+	} else if errors.Is(err, errNotOwner) {
+		messageCreate := cfg.NotOwnerMessage
+		messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+		return false, e.CreateMessage(messageCreate)
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func disable(e *handler.ComponentEvent) error {
+	components := handler.DisableComponents(e.Message.Components)
+	return e.UpdateMessage(discord.MessageUpdate{Components: &components})
+}
+
+// customID returns the custom id of the given action of a widget.
+func customID(prefix string, token string, action string) string {
+	return prefix + "/" + token + "/" + action
+}
+
+// pattern returns the route pattern of the actions of a widget.
+func pattern(prefix string) string {
+	return prefix + "/{" + handler.SessionVar + "}/{action}"
+}
+
+// render builds the message of a widget with the given body and controls.
+// With components v2 the body and controls are put into a discord.ContainerComponent, otherwise the content and embeds are used as body.
+func render(cfg config, body []discord.ContainerSubComponent, content string, embeds []discord.Embed, controls ...discord.ActionRowComponent) discord.MessageCreate {
+	if !cfg.ComponentsV2 {
+		components := make([]discord.LayoutComponent, len(controls))
+		for i, control := range controls {
+			components[i] = control
+		}
+		return discord.MessageCreate{
+			Content:    content,
+			Embeds:     embeds,
+			Components: components,
+		}
+	}
+
+	containerComponents := append([]discord.ContainerSubComponent{}, body...)
+	if len(controls) > 0 {
+		containerComponents = append(containerComponents, discord.NewSmallSeparator())
+	}
+	for _, control := range controls {
+		containerComponents = append(containerComponents, control)
+	}
+	return discord.MessageCreate{
+		Components: []discord.LayoutComponent{
+			discord.NewContainer(containerComponents...).WithAccentColor(cfg.AccentColor),
+		},
+		Flags: discord.MessageFlagIsComponentsV2,
+	}
+}
+
+// toMessageUpdate converts a message rendered by render into a discord.MessageUpdate.
+func toMessageUpdate(cfg config, messageCreate discord.MessageCreate) discord.MessageUpdate {
+	if cfg.ComponentsV2 {
+		return discord.MessageUpdate{Components: &messageCreate.Components}
+	}
+	return discord.MessageUpdate{
+		Content:    &messageCreate.Content,
+		Embeds:     &messageCreate.Embeds,
+		Components: &messageCreate.Components,
+	}
+}
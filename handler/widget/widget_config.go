@@ -0,0 +1,119 @@
+package widget
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func defaultConfig() config {
+	return config{
+		ComponentsV2: true,
+		NotOwnerMessage: discord.MessageCreate{
+			Content: "You can't use this, it belongs to someone else.",
+		},
+		ConfirmLabel: "Confirm",
+		CancelLabel:  "Cancel",
+		SubmitLabel:  "Submit",
+		CloseLabel:   "Close",
+		ExpiredMessage: discord.MessageCreate{
+			Content: "This has expired, please start again.",
+		},
+		ContinueLabel: "Continue",
+		BackLabel:     "Back",
+		WizardStepContent: func(step int, steps int, title string) string {
+			return fmt.Sprintf("Step %d of %d: **%s**", step, steps, title)
+		},
+		StaleStepMessage: "This step has already been submitted.",
+	}
+}
+
+type config struct {
+	ComponentsV2    bool
+	Ephemeral       bool
+	AccentColor     int
+	NotOwnerMessage discord.MessageCreate
+	ConfirmLabel    string
+	CancelLabel     string
+	SubmitLabel     string
+	CloseLabel      string
+	ExpiredMessage  discord.MessageCreate
+	ContinueLabel   string
+	BackLabel       string
+	// WizardStepContent returns the content of the message between two steps of a Wizard for the given step, starting at 1.
+	WizardStepContent func(step int, steps int, title string) string
+	StaleStepMessage  string
+}
+
+// ConfigOpt is a type alias for a function that takes a config and is used to configure your widgets.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithComponentsV2 sets whether widgets are rendered inside a discord.ContainerComponent with components v2 or with classic action rows below the content.
+// This is enabled by default.
+func WithComponentsV2(componentsV2 bool) ConfigOpt {
+	return func(config *config) {
+		config.ComponentsV2 = componentsV2
+	}
+}
+
+// WithEphemeral sets whether widgets are sent as ephemeral messages.
+// The components of ephemeral messages can't be disabled when the widget times out, but they are disabled on the next click.
+func WithEphemeral(ephemeral bool) ConfigOpt {
+	return func(config *config) {
+		config.Ephemeral = ephemeral
+	}
+}
+
+// WithAccentColor sets the accent color of the discord.ContainerComponent of widgets rendered with components v2.
+func WithAccentColor(color int) ConfigOpt {
+	return func(config *config) {
+		config.AccentColor = color
+	}
+}
+
+// WithNotOwnerMessage sets the ephemeral message sent to users which interact with a widget of another user.
+func WithNotOwnerMessage(messageCreate discord.MessageCreate) ConfigOpt {
+	return func(config *config) {
+		config.NotOwnerMessage = messageCreate
+	}
+}
+
+// WithButtonLabels sets the labels of the confirm, cancel and submit buttons of Confirm and Picker widgets and the close button of Paginator widgets.
+func WithButtonLabels(confirmLabel string, cancelLabel string, submitLabel string, closeLabel string) ConfigOpt {
+	return func(config *config) {
+		config.ConfirmLabel = confirmLabel
+		config.CancelLabel = cancelLabel
+		config.SubmitLabel = submitLabel
+		config.CloseLabel = closeLabel
+	}
+}
+
+// WithExpiredMessage sets the ephemeral message sent when a modal of a Wizard is submitted after its session has expired.
+func WithExpiredMessage(messageCreate discord.MessageCreate) ConfigOpt {
+	return func(config *config) {
+		config.ExpiredMessage = messageCreate
+	}
+}
+
+// WithWizardLabels sets the labels of the continue and back buttons of Wizard widgets.
+func WithWizardLabels(continueLabel string, backLabel string) ConfigOpt {
+	return func(config *config) {
+		config.ContinueLabel = continueLabel
+		config.BackLabel = backLabel
+	}
+}
+
+// WithWizardMessages sets the content of the message between two steps of Wizard widgets and the message shown when an outdated step is submitted.
+// The stepContent func gets the number of the next step, starting at 1, the total number of steps and the title of the next step.
+func WithWizardMessages(stepContent func(step int, steps int, title string) string, staleStepMessage string) ConfigOpt {
+	return func(config *config) {
+		config.WizardStepContent = stepContent
+		config.StaleStepMessage = staleStepMessage
+	}
+}
@@ -0,0 +1,321 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/handler/handlertest"
+)
+
+var otherUser = discord.User{ID: 1, Username: "other"}
+
+// slowSessionStore delays loading sessions, so concurrent interactions load a session before any of them saves or deletes it.
+type slowSessionStore struct {
+	handler.SessionStore
+}
+
+func (s slowSessionStore) Get(ctx context.Context, token string) (handler.Session, error) {
+	session, err := s.SessionStore.Get(ctx, token)
+	time.Sleep(10 * time.Millisecond)
+	return session, err
+}
+
+func TestConfirm(t *testing.T) {
+	type click struct {
+		user         discord.User
+		responseType discord.InteractionResponseType
+		ephemeral    bool
+	}
+
+	data := []struct {
+		name      string
+		ttl       time.Duration
+		clicks    []click
+		confirmed int
+	}{
+		{
+			name:      "confirm",
+			clicks:    []click{{responseType: discord.InteractionResponseTypeUpdateMessage}},
+			confirmed: 1,
+		},
+		{
+			name: "not owner",
+			clicks: []click{
+				{user: otherUser, responseType: discord.InteractionResponseTypeCreateMessage, ephemeral: true},
+				{responseType: discord.InteractionResponseTypeUpdateMessage},
+			},
+			confirmed: 1,
+		},
+		{
+			name:   "expired",
+			ttl:    -time.Second,
+			clicks: []click{{responseType: discord.InteractionResponseTypeUpdateMessage}},
+		},
+		{
+			name: "double submit",
+			clicks: []click{
+				{responseType: discord.InteractionResponseTypeUpdateMessage},
+				{responseType: discord.InteractionResponseTypeUpdateMessage},
+			},
+			confirmed: 1,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			ttl := d.ttl
+			if ttl == 0 {
+				ttl = time.Minute
+			}
+			mux := handler.New()
+			h := handlertest.New(mux)
+			sessions := handler.NewSessionManager(h.Client, handler.WithSessionTTL(ttl))
+
+			var confirmed int
+			NewConfirm("/confirm", sessions, func(e *handler.ComponentEvent, data string, ok bool) error {
+				if data != "data" || !ok {
+					t.Errorf("expected data and confirmed, got %q, %t", data, ok)
+				}
+				confirmed++
+				content := "done"
+				return e.UpdateMessage(discord.MessageUpdate{Content: &content})
+			}).Register(mux)
+
+			token := createSession(t, sessions, confirmState[string]{baseState: baseState{OwnerID: handlertest.DefaultUser.ID}, Data: "data"})
+			for i, c := range d.clicks {
+				user := handlertest.DefaultUser
+				if c.user.ID != 0 {
+					user = c.user
+				}
+				rec := h.Dispatch(handlertest.Button(customID("/confirm", token, "confirm"), handlertest.WithUser(user)))
+				if rec.Err() != nil {
+					t.Fatalf("click %d: %s", i, rec.Err())
+				}
+				if responseType, _ := rec.ResponseType(); responseType != c.responseType {
+					t.Errorf("click %d: expected response type %d, got %d", i, c.responseType, responseType)
+				}
+				if message := rec.Message(); message == nil || message.Flags.Has(discord.MessageFlagEphemeral) != c.ephemeral {
+					t.Errorf("click %d: expected a message with ephemeral %t, got %+v", i, c.ephemeral, message)
+				}
+			}
+			if confirmed != d.confirmed {
+				t.Errorf("expected the handler to be called %d times, got %d", d.confirmed, confirmed)
+			}
+		})
+	}
+}
+
+func TestConfirm_ConcurrentSubmit(t *testing.T) {
+	mux := handler.New()
+	h := handlertest.New(mux)
+	sessions := handler.NewSessionManager(h.Client, handler.WithSessionStore(slowSessionStore{SessionStore: handler.NewMemorySessionStore()}))
+
+	var confirmed atomic.Int32
+	NewConfirm("/confirm", sessions, func(e *handler.ComponentEvent, data string, ok bool) error {
+		confirmed.Add(1)
+		return e.DeferUpdateMessage()
+	}).Register(mux)
+
+	token := createSession(t, sessions, confirmState[string]{baseState: baseState{OwnerID: handlertest.DefaultUser.ID}})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rec := h.Dispatch(handlertest.Button(customID("/confirm", token, "confirm"))); rec.Err() != nil {
+				t.Error(rec.Err())
+			}
+		}()
+	}
+	wg.Wait()
+	if n := confirmed.Load(); n != 1 {
+		t.Errorf("expected the handler to be called once, got %d", n)
+	}
+}
+
+func TestPicker(t *testing.T) {
+	mux := handler.New()
+	h := handlertest.New(mux)
+	sessions := handler.NewSessionManager(h.Client)
+
+	var submitted [][]string
+	NewPicker("/pick", sessions, func(e *handler.ComponentEvent, data string, values []string, ok bool) error {
+		submitted = append(submitted, values)
+		return e.DeferUpdateMessage()
+	}).Register(mux)
+
+	token := createSession(t, sessions, pickerState[string]{
+		baseState: baseState{OwnerID: handlertest.DefaultUser.ID},
+		Prompt: PickerPrompt{
+			Options:   []discord.StringSelectMenuOption{discord.NewStringSelectMenuOption("A", "a"), discord.NewStringSelectMenuOption("B", "b")},
+			MaxValues: 2,
+		},
+	})
+	interactions := []discord.Interaction{
+		handlertest.StringSelectMenu(customID("/pick", token, "select"), []string{"b"}, handlertest.WithUser(otherUser)),
+		handlertest.StringSelectMenu(customID("/pick", token, "select"), []string{"a", "b"}),
+		handlertest.Button(customID("/pick", token, "submit")),
+		handlertest.Button(customID("/pick", token, "submit")),
+	}
+	for i, interaction := range interactions {
+		if rec := h.Dispatch(interaction); rec.Err() != nil {
+			t.Fatalf("interaction %d: %s", i, rec.Err())
+		}
+	}
+	if len(submitted) != 1 || !slices.Equal(submitted[0], []string{"a", "b"}) {
+		t.Errorf("expected a single submit of [a b], got %v", submitted)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	mux := handler.New()
+	h := handlertest.New(mux)
+	sessions := handler.NewSessionManager(h.Client)
+
+	NewPaginator("/pages", sessions, func(data []string) int {
+		return len(data)
+	}, func(data []string, page int) Page {
+		return Page{Content: data[page]}
+	}).Register(mux)
+
+	token := createSession(t, sessions, paginatorState[[]string]{
+		baseState: baseState{OwnerID: handlertest.DefaultUser.ID},
+		Data:      []string{"1", "2", "3"},
+	})
+	for _, action := range []string{"next", "next", "next"} {
+		if rec := h.Dispatch(handlertest.Button(customID("/pages", token, action))); rec.Err() != nil {
+			t.Fatal(rec.Err())
+		}
+	}
+	var state paginatorState[[]string]
+	if err := sessions.Load(context.Background(), token, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Page != 2 {
+		t.Errorf("expected page 2, got %d", state.Page)
+	}
+
+	if rec := h.Dispatch(handlertest.Button(customID("/pages", token, "close"))); rec.Err() != nil {
+		t.Fatal(rec.Err())
+	}
+	if err := sessions.Load(context.Background(), token, &state); err != handler.ErrSessionNotFound {
+		t.Errorf("expected the session to be deleted, got %v", err)
+	}
+}
+
+func TestWizard(t *testing.T) {
+	mux := handler.New()
+	h := handlertest.New(mux)
+	sessions := handler.NewSessionManager(h.Client)
+
+	var completed []string
+	NewWizard("/wizard", sessions, []WizardStep[string]{
+		{
+			Title:      "Name",
+			Components: func(data string) []discord.LayoutComponent { return nil },
+			Submit: func(modal discord.ModalSubmitInteractionData, data *string) error {
+				name := modal.Text("name")
+				if name == "" {
+					return ValidationErrorf("name is required")
+				}
+				*data = name
+				return nil
+			},
+		},
+		{
+			Title:      "Bio",
+			Components: func(data string) []discord.LayoutComponent { return nil },
+			Submit: func(modal discord.ModalSubmitInteractionData, data *string) error {
+				*data += ": " + modal.Text("bio")
+				return nil
+			},
+		},
+	}, func(e *handler.ModalEvent, data string) error {
+		completed = append(completed, data)
+		return e.CreateMessage(discord.MessageCreate{Content: data})
+	}, WithComponentsV2(false), WithWizardMessages(func(step int, steps int, title string) string {
+		return fmt.Sprintf("%d/%d %s", step, steps, title)
+	}, "stale")).Register(mux)
+
+	token := createSession(t, sessions, wizardState[string]{baseState: baseState{OwnerID: handlertest.DefaultUser.ID}})
+	submits := []struct {
+		step    string
+		user    discord.User
+		input   string
+		content string
+	}{
+		{step: "0", input: "", content: "1/2 Name\nname is required"},
+		{step: "0", user: otherUser, input: "Eve", content: "You can't use this, it belongs to someone else."},
+		{step: "0", input: "Ada", content: "2/2 Bio"},
+		{step: "0", input: "Ada", content: "stale"},
+		{step: "1", input: "hi", content: "Ada: hi"},
+		{step: "1", input: "hi", content: "This has expired, please start again."},
+	}
+	for i, s := range submits {
+		user := handlertest.DefaultUser
+		if s.user.ID != 0 {
+			user = s.user
+		}
+		customID := "/wizard/" + token + "/submit/" + s.step
+		rec := h.Dispatch(handlertest.Modal(customID, handlertest.WithUser(user), handlertest.WithTextInput("name", s.input), handlertest.WithTextInput("bio", s.input)))
+		if rec.Err() != nil {
+			t.Fatalf("submit %d: %s", i, rec.Err())
+		}
+		if message := rec.Message(); message == nil || message.Content != s.content {
+			t.Errorf("submit %d: expected a message with content %q, got %+v", i, s.content, message)
+		}
+	}
+	if !slices.Equal(completed, []string{"Ada: hi"}) {
+		t.Errorf("expected a single completion with \"Ada: hi\", got %q", completed)
+	}
+}
+
+func TestWizard_ConcurrentSubmit(t *testing.T) {
+	mux := handler.New()
+	h := handlertest.New(mux)
+	sessions := handler.NewSessionManager(h.Client, handler.WithSessionStore(slowSessionStore{SessionStore: handler.NewMemorySessionStore()}))
+
+	var completed atomic.Int32
+	NewWizard("/wizard", sessions, []WizardStep[string]{{
+		Title:      "Name",
+		Components: func(data string) []discord.LayoutComponent { return nil },
+		Submit: func(modal discord.ModalSubmitInteractionData, data *string) error {
+			return nil
+		},
+	}}, func(e *handler.ModalEvent, data string) error {
+		completed.Add(1)
+		return e.CreateMessage(discord.MessageCreate{Content: data})
+	}).Register(mux)
+
+	token := createSession(t, sessions, wizardState[string]{baseState: baseState{OwnerID: handlertest.DefaultUser.ID}})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rec := h.Dispatch(handlertest.Modal("/wizard/" + token + "/submit/0")); rec.Err() != nil {
+				t.Error(rec.Err())
+			}
+		}()
+	}
+	wg.Wait()
+	if n := completed.Load(); n != 1 {
+		t.Errorf("expected the wizard to be completed once, got %d", n)
+	}
+}
+
+func createSession(t *testing.T, sessions *handler.SessionManager, state any) string {
+	t.Helper()
+	token, err := sessions.Create(context.Background(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
@@ -0,0 +1,298 @@
+package widget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// errStaleStep is returned by the update func of Wizard.handleModal if an outdated modal of a Wizard is submitted.
+var errStaleStep = errors.New("wizard step has already been submitted")
+
+// ValidationError is returned by WizardStep.Submit to reject the submitted values. The step is prompted again with the Message.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrorf returns a *ValidationError with the message formatted according to the format specifier.
+func ValidationErrorf(format string, a ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, a...)}
+}
+
+// WizardStep is a single modal of a Wizard.
+type WizardStep[T any] struct {
+	Title string
+	// Components returns the components of the modal, like discord.LabelComponent(s) with text inputs or select menus.
+	// The data holds the answers of the previous steps, so components can be prefilled when the user goes back.
+	// Text inputs are prefilled with the rejected values automatically when the step is prompted again after a ValidationError.
+	Components func(data T) []discord.LayoutComponent
+	// Submit validates the submitted modal and stores the answers in data.
+	// Returning a *ValidationError prompts the step again, all other errors are returned to the handler.Mux.
+	Submit func(modal discord.ModalSubmitInteractionData, data *T) error
+}
+
+// WizardCompleteHandler handles the data of a Wizard once all steps have been submitted. The handler must respond to the interaction.
+type WizardCompleteHandler[T any] func(e *handler.ModalEvent, data T) error
+
+// ModalResponder is an interaction event a Wizard can be started from, like *handler.CommandEvent or *handler.ComponentEvent.
+type ModalResponder interface {
+	User() discord.User
+	Modal(modalCreate discord.ModalCreate, opts ...rest.RequestOpt) error
+}
+
+type wizardState[T any] struct {
+	baseState
+	Data T   `json:"data"`
+	Step int `json:"step"`
+	// Values are the rejected text input values of the current step, which are prefilled when it is prompted again.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// NewWizard returns a new Wizard with the given route prefix, which prompts the given steps one after another and calls the WizardCompleteHandler with the accumulated data.
+// It panics if there are no steps.
+func NewWizard[T any](prefix string, sessions *handler.SessionManager, steps []WizardStep[T], complete WizardCompleteHandler[T], opts ...ConfigOpt) *Wizard[T] {
+	if len(steps) == 0 {
+		panic("wizard must have at least one step")
+	}
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Wizard[T]{
+		prefix:   prefix,
+		sessions: sessions,
+		steps:    steps,
+		complete: complete,
+		config:   cfg,
+	}
+}
+
+// Wizard chains multiple modals into a single flow. The answers of all steps are accumulated in T, which is kept in a handler.Session.
+// As a modal can't be opened in response to another modal, each submitted step is followed by a message with buttons to continue, go back or cancel.
+type Wizard[T any] struct {
+	prefix   string
+	sessions *handler.SessionManager
+	steps    []WizardStep[T]
+	complete WizardCompleteHandler[T]
+	config   config
+}
+
+// Register registers the routes of the Wizard to the given handler.Router.
+func (w *Wizard[T]) Register(r handler.Router) {
+	r.Component(pattern(w.prefix), w.handleComponent)
+	r.Modal(w.prefix+"/{"+handler.SessionVar+"}/submit/{step}", w.handleModal)
+}
+
+// Start responds to the given interaction with the modal of the first step. The data is the initial state of the answers. The user of the interaction owns the Wizard.
+func (w *Wizard[T]) Start(ctx context.Context, e ModalResponder, data T) error {
+	state := wizardState[T]{
+		baseState: baseState{OwnerID: e.User().ID},
+		Data:      data,
+	}
+	token, err := w.sessions.Create(ctx, state)
+	if err != nil {
+		return err
+	}
+	if err = e.Modal(w.modal(token, state), rest.WithCtx(ctx)); err != nil {
+		_ = w.sessions.Delete(ctx, token)
+		return err
+	}
+	return nil
+}
+
+func (w *Wizard[T]) handleComponent(e *handler.ComponentEvent) error {
+	var state wizardState[T]
+	action := e.Vars["action"]
+	ok, err := updateState(e, w.sessions, w.config, &state, func() error {
+		switch action {
+		case "continue":
+			return nil
+		case "back":
+			state.Step = max(state.Step-1, 0)
+			state.Values = nil
+			return nil
+		case "cancel":
+			return handler.ErrDeleteSession
+		}
+		return fmt.Errorf("unknown wizard action %q", action)
+	})
+	if !ok || err != nil {
+		return err
+	}
+
+	if action == "cancel" {
+		return disable(e)
+	}
+	return e.Modal(w.modal(e.Vars[handler.SessionVar], state))
+}
+
+func (w *Wizard[T]) handleModal(e *handler.ModalEvent) error {
+	token := e.Vars[handler.SessionVar]
+	var (
+		state         wizardState[T]
+		validationErr *ValidationError
+		completed     bool
+	)
+	err := w.sessions.Update(e.Ctx, token, &state, func() error {
+		if e.User().ID != state.OwnerID {
+			return errNotOwner
+		}
+		// an outdated modal was submitted, e.g. after going back in another modal
+		if step, err := strconv.Atoi(e.Vars["step"]); err != nil || step != state.Step {
+			return errStaleStep
+		}
+
+		data := state.Data
+		err := w.steps[state.Step].Submit(e.Data, &data)
+		if errors.As(err, &validationErr) {
+			state.Values = textInputValues(e.Data)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		state.Data = data
+		state.Values = nil
+		// the session is deleted under its lock, so the last step can only be completed once
+		if state.Step == len(w.steps)-1 {
+			completed = true
+			return handler.ErrDeleteSession
+		}
+		state.Step++
+		return nil
+	})
+	if errors.Is(err, handler.ErrSessionNotFound) {
+		if e.Message != nil {
+			components := handler.DisableComponents(e.Message.Components)
+			return e.UpdateMessage(discord.MessageUpdate{Components: &components})
+		}
+		messageCreate := w.config.ExpiredMessage
+		messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+		return e.CreateMessage(messageCreate)
+	} else if errors.Is(err, errNotOwner) {
+		messageCreate := w.config.NotOwnerMessage
+		messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+		return e.CreateMessage(messageCreate)
+	} else if errors.Is(err, errStaleStep) {
+		if e.Message != nil {
+			return e.UpdateMessage(toMessageUpdate(w.config, w.message(token, state, w.config.StaleStepMessage)))
+		}
+		// the message of the Wizard already exists, so don't create and attach another one
+		return e.CreateMessage(discord.MessageCreate{
+			Content: w.config.StaleStepMessage,
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	} else if err != nil {
+		return err
+	}
+
+	if validationErr != nil {
+		return w.respond(e, token, w.message(token, state, validationErr.Message))
+	}
+	if completed {
+		return w.complete(e, state.Data)
+	}
+	return w.respond(e, token, w.message(token, state, ""))
+}
+
+// respond updates the message of the Wizard if the modal was opened from it, otherwise a new message is created and attached to the session.
+func (w *Wizard[T]) respond(e *handler.ModalEvent, token string, messageCreate discord.MessageCreate) error {
+	if e.Message != nil {
+		return e.UpdateMessage(toMessageUpdate(w.config, messageCreate))
+	}
+	if w.config.Ephemeral {
+		messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+	}
+	if err := e.CreateMessage(messageCreate); err != nil {
+		return err
+	}
+	if w.config.Ephemeral {
+		return nil
+	}
+	message, err := e.GetInteractionResponse()
+	if err != nil {
+		return err
+	}
+	return w.sessions.Attach(e.Ctx, token, message.ChannelID, message.ID)
+}
+
+func (w *Wizard[T]) modal(token string, state wizardState[T]) discord.ModalCreate {
+	step := w.steps[state.Step]
+	return discord.ModalCreate{
+		CustomID:   w.prefix + "/" + token + "/submit/" + strconv.Itoa(state.Step),
+		Title:      step.Title,
+		Components: prefillTextInputs(step.Components(state.Data), state.Values),
+	}
+}
+
+// message renders the message between two steps, which shows the next step or the given validation error.
+func (w *Wizard[T]) message(token string, state wizardState[T], validationErr string) discord.MessageCreate {
+	content := w.config.WizardStepContent(state.Step+1, len(w.steps), w.steps[state.Step].Title)
+	if validationErr != "" {
+		content += "\n" + validationErr
+	}
+
+	buttons := []discord.InteractiveComponent{
+		discord.NewPrimaryButton(w.config.ContinueLabel, customID(w.prefix, token, "continue")),
+	}
+	if state.Step > 0 {
+		buttons = append(buttons, discord.NewSecondaryButton(w.config.BackLabel, customID(w.prefix, token, "back")))
+	}
+	buttons = append(buttons, discord.NewDangerButton(w.config.CancelLabel, customID(w.prefix, token, "cancel")))
+
+	return render(w.config, []discord.ContainerSubComponent{discord.NewTextDisplay(content)}, content, nil, discord.NewActionRow(buttons...))
+}
+
+func textInputValues(data discord.ModalSubmitInteractionData) map[string]string {
+	values := map[string]string{}
+	for component := range data.AllComponents() {
+		if textInput, ok := component.(discord.TextInputComponent); ok {
+			values[textInput.CustomID] = textInput.Value
+		}
+	}
+	return values
+}
+
+// prefillTextInputs sets the values of the text inputs in labels and action rows to the given values by their custom id.
+func prefillTextInputs(components []discord.LayoutComponent, values map[string]string) []discord.LayoutComponent {
+	if len(values) == 0 {
+		return components
+	}
+	prefilled := make([]discord.LayoutComponent, len(components))
+	for i, component := range components {
+		switch c := component.(type) {
+		case discord.LabelComponent:
+			if textInput, ok := c.Component.(discord.TextInputComponent); ok {
+				if value, ok := values[textInput.CustomID]; ok {
+					textInput.Value = value
+					c.Component = textInput
+				}
+			}
+			prefilled[i] = c
+		case discord.ActionRowComponent:
+			subComponents := make([]discord.InteractiveComponent, len(c.Components))
+			for j, subComponent := range c.Components {
+				if textInput, ok := subComponent.(discord.TextInputComponent); ok {
+					if value, ok := values[textInput.CustomID]; ok {
+						textInput.Value = value
+						subComponent = textInput
+					}
+				}
+				subComponents[j] = subComponent
+			}
+			c.Components = subComponents
+			prefilled[i] = c
+		default:
+			prefilled[i] = component
+		}
+	}
+	return prefilled
+}
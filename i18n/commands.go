@@ -0,0 +1,153 @@
+package i18n
+
+import (
+	"maps"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// LocalizeCommands returns copies of the given commands with their name & description localizations, and those of their options and choices, filled from the Catalog.
+// Localizations which are already set take precedence over the Catalog. The messages are looked up with the following keys:
+//
+//	commands.<command>.name
+//	commands.<command>.description
+//	commands.<command>.options.<option>.name
+//	commands.<command>.options.<option>.description
+//	commands.<command>.options.<option>.choices.<choice>
+//	commands.<command>.options.<subcommand>.options.<option>.name
+//
+// where <command>, <option> and <choice> are the default names and "commands" is the prefix set via WithCommandKeyPrefix.
+// Localize the commands before passing them to handler.SyncCommands or handler.SyncCommandsDiff.
+func (c *Catalog) LocalizeCommands(commands []discord.ApplicationCommandCreate) []discord.ApplicationCommandCreate {
+	localized := make([]discord.ApplicationCommandCreate, len(commands))
+	for i, command := range commands {
+		localized[i] = c.localizeCommand(command)
+	}
+	return localized
+}
+
+func (c *Catalog) localizeCommand(command discord.ApplicationCommandCreate) discord.ApplicationCommandCreate {
+	prefix := c.config.CommandKeyPrefix + "." + command.CommandName()
+	switch cmd := command.(type) {
+	case discord.SlashCommandCreate:
+		cmd.NameLocalizations = c.mergeLocalizations(cmd.NameLocalizations, prefix+".name")
+		cmd.DescriptionLocalizations = c.mergeLocalizations(cmd.DescriptionLocalizations, prefix+".description")
+		cmd.Options = c.localizeOptions(cmd.Options, prefix)
+		return cmd
+	case discord.UserCommandCreate:
+		cmd.NameLocalizations = c.mergeLocalizations(cmd.NameLocalizations, prefix+".name")
+		return cmd
+	case discord.MessageCommandCreate:
+		cmd.NameLocalizations = c.mergeLocalizations(cmd.NameLocalizations, prefix+".name")
+		return cmd
+	case discord.EntryPointCommandCreate:
+		cmd.NameLocalizations = c.mergeLocalizations(cmd.NameLocalizations, prefix+".name")
+		return cmd
+	}
+	return command
+}
+
+func (c *Catalog) localizeOptions(options []discord.ApplicationCommandOption, prefix string) []discord.ApplicationCommandOption {
+	if options == nil {
+		return nil
+	}
+	localized := make([]discord.ApplicationCommandOption, len(options))
+	for i, option := range options {
+		localized[i] = c.localizeOption(option, prefix+".options."+option.OptionName())
+	}
+	return localized
+}
+
+func (c *Catalog) localizeOption(option discord.ApplicationCommandOption, prefix string) discord.ApplicationCommandOption {
+	switch o := option.(type) {
+	case discord.ApplicationCommandOptionSubCommand:
+		return c.localizeSubCommand(o, prefix)
+	case discord.ApplicationCommandOptionSubCommandGroup:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		if o.Options != nil {
+			subCommands := make([]discord.ApplicationCommandOptionSubCommand, len(o.Options))
+			for i, subCommand := range o.Options {
+				subCommands[i] = c.localizeSubCommand(subCommand, prefix+".options."+subCommand.Name)
+			}
+			o.Options = subCommands
+		}
+		return o
+	case discord.ApplicationCommandOptionString:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		o.Choices = localizeChoices(c, o.Choices, prefix, func(choice *discord.ApplicationCommandOptionChoiceString) (string, *map[discord.Locale]string) {
+			return choice.Name, &choice.NameLocalizations
+		})
+		return o
+	case discord.ApplicationCommandOptionInt:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		o.Choices = localizeChoices(c, o.Choices, prefix, func(choice *discord.ApplicationCommandOptionChoiceInt) (string, *map[discord.Locale]string) {
+			return choice.Name, &choice.NameLocalizations
+		})
+		return o
+	case discord.ApplicationCommandOptionFloat:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		o.Choices = localizeChoices(c, o.Choices, prefix, func(choice *discord.ApplicationCommandOptionChoiceFloat) (string, *map[discord.Locale]string) {
+			return choice.Name, &choice.NameLocalizations
+		})
+		return o
+	case discord.ApplicationCommandOptionBool:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	case discord.ApplicationCommandOptionUser:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	case discord.ApplicationCommandOptionChannel:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	case discord.ApplicationCommandOptionRole:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	case discord.ApplicationCommandOptionMentionable:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	case discord.ApplicationCommandOptionAttachment:
+		o.NameLocalizations = c.mergeLocalizations(o.NameLocalizations, prefix+".name")
+		o.DescriptionLocalizations = c.mergeLocalizations(o.DescriptionLocalizations, prefix+".description")
+		return o
+	}
+	return option
+}
+
+func (c *Catalog) localizeSubCommand(subCommand discord.ApplicationCommandOptionSubCommand, prefix string) discord.ApplicationCommandOptionSubCommand {
+	subCommand.NameLocalizations = c.mergeLocalizations(subCommand.NameLocalizations, prefix+".name")
+	subCommand.DescriptionLocalizations = c.mergeLocalizations(subCommand.DescriptionLocalizations, prefix+".description")
+	subCommand.Options = c.localizeOptions(subCommand.Options, prefix)
+	return subCommand
+}
+
+func localizeChoices[T any](c *Catalog, choices []T, prefix string, fields func(choice *T) (string, *map[discord.Locale]string)) []T {
+	if choices == nil {
+		return nil
+	}
+	localized := make([]T, len(choices))
+	for i, choice := range choices {
+		name, localizations := fields(&choice)
+		*localizations = c.mergeLocalizations(*localizations, prefix+".choices."+name)
+		localized[i] = choice
+	}
+	return localized
+}
+
+// mergeLocalizations returns a copy of the given localizations with the missing locales filled from the Catalog.
+func (c *Catalog) mergeLocalizations(localizations map[discord.Locale]string, key string) map[discord.Locale]string {
+	catalogLocalizations := c.Localizations(key)
+	if len(catalogLocalizations) == 0 {
+		return localizations
+	}
+	maps.Copy(catalogLocalizations, localizations)
+	return catalogLocalizations
+}
@@ -0,0 +1,272 @@
+// Package i18n provides message catalogues for localizing application commands and interaction responses.
+//
+// A Catalog holds the messages of each discord.Locale by key. Messages are loaded from files named after their locale,
+// like en-US.json or de.json, in which nested objects are flattened into dotted keys:
+//
+//	{"ping": {"reply": "Pong! %dms"}}
+//
+// is looked up with the key "ping.reply".
+//
+// Catalog.LocalizeCommands fills the name & description localizations of commands before they are synced,
+// and a Localizer translates messages for the locale of an interaction, following a fallback chain if a message is missing.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// UnmarshalFunc decodes a message file into the given value, like json.Unmarshal.
+type UnmarshalFunc func(data []byte, v any) error
+
+// New returns a new empty Catalog configured with the given ConfigOpt(s).
+func New(opts ...ConfigOpt) *Catalog {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Catalog{
+		config:   cfg,
+		messages: map[discord.Locale]map[string]string{},
+	}
+}
+
+// Catalog holds the messages of all locales. It is safe for concurrent use.
+type Catalog struct {
+	config config
+
+	mu       sync.RWMutex
+	messages map[discord.Locale]map[string]string
+}
+
+// DefaultLocale returns the discord.Locale which ends every fallback chain.
+func (c *Catalog) DefaultLocale() discord.Locale {
+	return c.config.DefaultLocale
+}
+
+// Add adds or overwrites a single message.
+func (c *Catalog) Add(locale discord.Locale, key string, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(locale, map[string]string{key: message})
+}
+
+// Load decodes the given message file data with the given UnmarshalFunc and adds its messages to the given discord.Locale.
+// Nested objects are flattened into dotted keys, all values must be strings.
+func (c *Catalog) Load(locale discord.Locale, data []byte, unmarshal UnmarshalFunc) error {
+	var v map[string]any
+	if err := unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode messages of locale %s: %w", locale.Code(), err)
+	}
+	messages := map[string]string{}
+	if err := flattenMessages(messages, "", v); err != nil {
+		return fmt.Errorf("failed to load messages of locale %s: %w", locale.Code(), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(locale, messages)
+	return nil
+}
+
+// LoadFS loads all message files in the given directory of the fs.FS. The name of each file without its extension is its discord.Locale, like de.json or pt-BR.toml.
+// Files are decoded with the UnmarshalFunc registered for their extension via WithDecoder, files with other extensions are ignored.
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		unmarshal, ok := c.config.Decoders[ext]
+		if !ok {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err = c.Load(discord.Locale(strings.TrimSuffix(entry.Name(), ext)), data, unmarshal); err != nil {
+			return fmt.Errorf("failed to load %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Locales returns all locales which have messages.
+func (c *Catalog) Locales() []discord.Locale {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Sorted(maps.Keys(c.messages))
+}
+
+// Message returns the message of the given discord.Locale without following any fallback chain.
+func (c *Catalog) Message(locale discord.Locale, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	message, ok := c.messages[locale][key]
+	return message, ok
+}
+
+// Localizations returns the message of every locale supported by Discord which has the given key, as used by the name & description localizations of commands.
+// Fallback chains are not followed, as Discord itself falls back to the default name or description.
+func (c *Catalog) Localizations(key string) map[discord.Locale]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var localizations map[discord.Locale]string
+	for locale, messages := range c.messages {
+		if _, ok := discord.Locales[locale]; !ok || locale == discord.LocaleUnknown {
+			continue
+		}
+		message, ok := messages[key]
+		if !ok {
+			continue
+		}
+		if localizations == nil {
+			localizations = map[discord.Locale]string{}
+		}
+		localizations[locale] = message
+	}
+	return localizations
+}
+
+// FallbackChain returns the locales which are tried in order for the given locales:
+// each locale followed by its fallbacks set via WithFallbacks and its base language like "pt" for "pt-BR", and finally the default locale.
+func (c *Catalog) FallbackChain(locales ...discord.Locale) []discord.Locale {
+	var chain []discord.Locale
+	addLocale := func(locale discord.Locale) {
+		if locale != discord.LocaleUnknown && !slices.Contains(chain, locale) {
+			chain = append(chain, locale)
+		}
+	}
+	for _, locale := range locales {
+		addLocale(locale)
+		for _, fallback := range c.config.Fallbacks[locale] {
+			addLocale(fallback)
+		}
+		if base, _, ok := strings.Cut(locale.Code(), "-"); ok {
+			addLocale(discord.Locale(base))
+		}
+	}
+	addLocale(c.config.DefaultLocale)
+	return chain
+}
+
+// Translate returns the message with the given key of the first locale of the fallback chain of the given discord.Locale which has it.
+// The message is formatted with fmt.Sprintf if args are passed. If no locale has the message, the key is returned without formatting.
+func (c *Catalog) Translate(locale discord.Locale, key string, args ...any) string {
+	return c.Localizer(locale).T(key, args...)
+}
+
+// Localizer returns a Localizer which follows the fallback chain of the given locales.
+func (c *Catalog) Localizer(locales ...discord.Locale) *Localizer {
+	return &Localizer{
+		catalog: c,
+		chain:   c.FallbackChain(locales...),
+	}
+}
+
+// InteractionLocalizer returns a Localizer for the given discord.Interaction, which prefers the locale of the user over the locale of the guild.
+func (c *Catalog) InteractionLocalizer(interaction discord.Interaction) *Localizer {
+	locales := []discord.Locale{interaction.Locale()}
+	if guildLocale := interaction.GuildLocale(); guildLocale != nil {
+		locales = append(locales, *guildLocale)
+	}
+	return c.Localizer(locales...)
+}
+
+func (c *Catalog) add(locale discord.Locale, messages map[string]string) {
+	if _, ok := c.messages[locale]; !ok {
+		c.messages[locale] = map[string]string{}
+	}
+	maps.Copy(c.messages[locale], messages)
+}
+
+func flattenMessages(messages map[string]string, prefix string, v map[string]any) error {
+	for key, value := range v {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case string:
+			messages[key] = value
+		case map[string]any:
+			if err := flattenMessages(messages, key, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q must be a string or an object, got %T", key, value)
+		}
+	}
+	return nil
+}
+
+// Localizer translates messages following a fixed fallback chain of locales.
+// A nil *Localizer returns the keys of all messages.
+type Localizer struct {
+	catalog *Catalog
+	chain   []discord.Locale
+}
+
+// Locale returns the preferred discord.Locale of the Localizer.
+func (l *Localizer) Locale() discord.Locale {
+	if l == nil || len(l.chain) == 0 {
+		return discord.LocaleUnknown
+	}
+	return l.chain[0]
+}
+
+// T returns the message with the given key of the first locale of the fallback chain which has it.
+// The message is formatted with fmt.Sprintf if args are passed. If no locale has the message, the key is returned without formatting.
+func (l *Localizer) T(key string, args ...any) string {
+	if l == nil {
+		return key
+	}
+	message, ok := l.lookup(key)
+	if !ok {
+		l.catalog.config.Logger.Debug("missing message", slog.String("key", key), slog.Any("locales", l.chain))
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+func (l *Localizer) lookup(key string) (string, bool) {
+	l.catalog.mu.RLock()
+	defer l.catalog.mu.RUnlock()
+	for _, locale := range l.chain {
+		if message, ok := l.catalog.messages[locale][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+type localizerKey struct{}
+
+// NewContext returns a copy of the given context.Context which carries the given Localizer.
+func NewContext(ctx context.Context, localizer *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, localizer)
+}
+
+// FromContext returns the Localizer of the given context.Context or nil if it has none.
+func FromContext(ctx context.Context) *Localizer {
+	if ctx == nil {
+		return nil
+	}
+	localizer, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return localizer
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"log/slog"
+
+	"github.com/disgoorg/json/v2"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger:        slog.Default(),
+		DefaultLocale: discord.LocaleEnglishUS,
+		Fallbacks: map[discord.Locale][]discord.Locale{
+			discord.LocaleEnglishGB: {discord.LocaleEnglishUS},
+		},
+		Decoders: map[string]UnmarshalFunc{
+			".json": json.Unmarshal,
+		},
+		CommandKeyPrefix: "commands",
+	}
+}
+
+type config struct {
+	Logger           *slog.Logger
+	DefaultLocale    discord.Locale
+	Fallbacks        map[discord.Locale][]discord.Locale
+	Decoders         map[string]UnmarshalFunc
+	CommandKeyPrefix string
+}
+
+// ConfigOpt is a type alias for a function that takes a config and is used to configure your Catalog.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "i18n"))
+}
+
+// WithLogger lets you inject your own logger implementing *slog.Logger.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithDefaultLocale sets the discord.Locale which is used when a message is missing in all other locales of a fallback chain.
+// This is discord.LocaleEnglishUS by default.
+func WithDefaultLocale(locale discord.Locale) ConfigOpt {
+	return func(config *config) {
+		config.DefaultLocale = locale
+	}
+}
+
+// WithFallbacks sets the discord.Locale(s) which are tried in order when a message is missing in the given discord.Locale, before the default locale is tried.
+// By default, discord.LocaleEnglishGB falls back to discord.LocaleEnglishUS.
+func WithFallbacks(locale discord.Locale, fallbacks ...discord.Locale) ConfigOpt {
+	return func(config *config) {
+		config.Fallbacks[locale] = fallbacks
+	}
+}
+
+// WithDecoder registers the UnmarshalFunc used by Catalog.LoadFS for message files with the given extension like ".toml".
+// Only ".json" is supported by default, so decoding TOML files requires passing the Unmarshal func of a TOML library.
+func WithDecoder(ext string, unmarshal UnmarshalFunc) ConfigOpt {
+	return func(config *config) {
+		config.Decoders[ext] = unmarshal
+	}
+}
+
+// WithCommandKeyPrefix sets the prefix of the message keys Catalog.LocalizeCommands looks up. This is "commands" by default.
+func WithCommandKeyPrefix(prefix string) ConfigOpt {
+	return func(config *config) {
+		config.CommandKeyPrefix = prefix
+	}
+}
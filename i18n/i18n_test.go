@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestCatalog_FallbackChain(t *testing.T) {
+	c := New(WithFallbacks(discord.LocaleFrench, discord.LocaleGerman))
+
+	data := []struct {
+		locales  []discord.Locale
+		expected []discord.Locale
+	}{
+		{locales: []discord.Locale{discord.LocaleEnglishUS}, expected: []discord.Locale{discord.LocaleEnglishUS, "en"}},
+		{locales: []discord.Locale{discord.LocaleEnglishGB}, expected: []discord.Locale{discord.LocaleEnglishGB, discord.LocaleEnglishUS, "en"}},
+		{locales: []discord.Locale{discord.LocalePortugueseBR}, expected: []discord.Locale{discord.LocalePortugueseBR, "pt", discord.LocaleEnglishUS}},
+		{locales: []discord.Locale{discord.LocaleFrench}, expected: []discord.Locale{discord.LocaleFrench, discord.LocaleGerman, discord.LocaleEnglishUS}},
+		{locales: []discord.Locale{discord.LocaleGerman, discord.LocaleFrench}, expected: []discord.Locale{discord.LocaleGerman, discord.LocaleFrench, discord.LocaleEnglishUS}},
+		{locales: []discord.Locale{discord.LocaleUnknown}, expected: []discord.Locale{discord.LocaleEnglishUS}},
+	}
+
+	for _, d := range data {
+		if chain := c.FallbackChain(d.locales...); !slices.Equal(chain, d.expected) {
+			t.Errorf("expected %v for %v, got %v", d.expected, d.locales, chain)
+		}
+	}
+}
+
+func TestCatalog_LoadFS(t *testing.T) {
+	c := New()
+	err := c.LoadFS(fstest.MapFS{
+		"locales/de.json":    {Data: []byte(`{"hello": "Hallo", "ping": {"reply": "Pong! %dms", "error": {"timeout": "Zeitüberschreitung"}}}`)},
+		"locales/en-US.json": {Data: []byte(`{"hello": "Hello"}`)},
+		"locales/README.md":  {Data: []byte(`# Locales`)},
+	}, "locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []struct {
+		locale   discord.Locale
+		key      string
+		expected string
+		ok       bool
+	}{
+		{locale: discord.LocaleGerman, key: "hello", expected: "Hallo", ok: true},
+		{locale: discord.LocaleGerman, key: "ping.reply", expected: "Pong! %dms", ok: true},
+		{locale: discord.LocaleGerman, key: "ping.error.timeout", expected: "Zeitüberschreitung", ok: true},
+		{locale: discord.LocaleGerman, key: "ping", ok: false},
+		{locale: discord.LocaleEnglishUS, key: "hello", expected: "Hello", ok: true},
+	}
+	for _, d := range data {
+		if message, ok := c.Message(d.locale, d.key); message != d.expected || ok != d.ok {
+			t.Errorf("expected %q, %t for %s %s, got %q, %t", d.expected, d.ok, d.locale, d.key, message, ok)
+		}
+	}
+	if locales := c.Locales(); !slices.Equal(locales, []discord.Locale{discord.LocaleGerman, discord.LocaleEnglishUS}) {
+		t.Errorf("expected locales de and en-US, got %v", locales)
+	}
+
+	err = c.LoadFS(fstest.MapFS{
+		"locales/fr.json": {Data: []byte(`{"count": 1}`)},
+	}, "locales")
+	if err == nil {
+		t.Error("expected error for non string message")
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	c := New()
+	c.Add(discord.LocaleEnglishUS, "ping.reply", "Pong! %dms")
+	c.Add(discord.LocaleEnglishUS, "hello", "Hello")
+	c.Add(discord.LocaleGerman, "hello", "Hallo")
+
+	data := []struct {
+		name      string
+		localizer *Localizer
+		key       string
+		args      []any
+		expected  string
+	}{
+		{name: "locale", localizer: c.Localizer(discord.LocaleGerman), key: "hello", expected: "Hallo"},
+		{name: "fallback", localizer: c.Localizer(discord.LocaleGerman), key: "ping.reply", args: []any{42}, expected: "Pong! 42ms"},
+		{name: "missing", localizer: c.Localizer(discord.LocaleGerman), key: "missing", expected: "missing"},
+		{name: "missing with args", localizer: c.Localizer(discord.LocaleGerman), key: "missing", args: []any{42}, expected: "missing"},
+		{name: "nil localizer", key: "hello", args: []any{42}, expected: "hello"},
+	}
+	for _, d := range data {
+		if message := d.localizer.T(d.key, d.args...); message != d.expected {
+			t.Errorf("%s: expected %q, got %q", d.name, d.expected, message)
+		}
+	}
+}
+
+func TestCatalog_LocalizeCommands(t *testing.T) {
+	c := New()
+	c.Add(discord.LocaleGerman, "commands.ping.name", "pingen")
+	c.Add(discord.LocaleGerman, "commands.ping.description", "Pingt den Bot")
+	c.Add(discord.LocaleFrench, "commands.ping.description", "Pinger le bot")
+	c.Add(discord.LocaleGerman, "commands.ping.options.unit.name", "einheit")
+	c.Add(discord.LocaleGerman, "commands.ping.options.unit.choices.seconds", "Sekunden")
+	c.Add(discord.LocaleGerman, "commands.ping.options.history.options.limit.name", "limit-de")
+	// not a discord.Locale, so it is never used for commands
+	c.Add("pt", "commands.ping.name", "pingar")
+
+	commands := c.LocalizeCommands([]discord.ApplicationCommandCreate{
+		discord.SlashCommandCreate{
+			Name:        "ping",
+			Description: "Pings the bot",
+			DescriptionLocalizations: map[discord.Locale]string{
+				discord.LocaleGerman: "Eigene Beschreibung",
+			},
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionString{
+					Name:        "unit",
+					Description: "The unit",
+					Choices: []discord.ApplicationCommandOptionChoiceString{
+						{Name: "seconds", Value: "s"},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:        "history",
+					Description: "The history",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionInt{Name: "limit", Description: "The limit"},
+					},
+				},
+			},
+		},
+	})
+	command := commands[0].(discord.SlashCommandCreate)
+	unit := command.Options[0].(discord.ApplicationCommandOptionString)
+	limit := command.Options[1].(discord.ApplicationCommandOptionSubCommand).Options[0].(discord.ApplicationCommandOptionInt)
+
+	data := []struct {
+		name     string
+		actual   map[discord.Locale]string
+		expected map[discord.Locale]string
+	}{
+		{name: "command name", actual: command.NameLocalizations, expected: map[discord.Locale]string{discord.LocaleGerman: "pingen"}},
+		// localizations set on the command take precedence over the catalog
+		{name: "command description", actual: command.DescriptionLocalizations, expected: map[discord.Locale]string{discord.LocaleGerman: "Eigene Beschreibung", discord.LocaleFrench: "Pinger le bot"}},
+		{name: "option name", actual: unit.NameLocalizations, expected: map[discord.Locale]string{discord.LocaleGerman: "einheit"}},
+		{name: "option description", actual: unit.DescriptionLocalizations},
+		{name: "choice name", actual: unit.Choices[0].NameLocalizations, expected: map[discord.Locale]string{discord.LocaleGerman: "Sekunden"}},
+		{name: "subcommand option name", actual: limit.NameLocalizations, expected: map[discord.Locale]string{discord.LocaleGerman: "limit-de"}},
+	}
+	for _, d := range data {
+		if !maps.Equal(d.actual, d.expected) {
+			t.Errorf("%s: expected %v, got %v", d.name, d.expected, d.actual)
+		}
+	}
+}
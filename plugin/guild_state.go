@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// NewGuildState returns a new GuildState which returns the value of newFunc for guilds without state.
+// If newFunc is nil, the zero value of T is returned.
+func NewGuildState[T any](newFunc func(guildID snowflake.ID) T) *GuildState[T] {
+	return &GuildState[T]{
+		newFunc: newFunc,
+		states:  map[snowflake.ID]T{},
+	}
+}
+
+// GuildState is a concurrency safe store for per guild state of a plugin.
+type GuildState[T any] struct {
+	newFunc func(guildID snowflake.ID) T
+
+	mu     sync.RWMutex
+	states map[snowflake.ID]T
+}
+
+// Get returns the state of the given guild or a new state if the guild has none yet.
+func (s *GuildState[T]) Get(guildID snowflake.ID) T {
+	s.mu.RLock()
+	state, ok := s.states[guildID]
+	s.mu.RUnlock()
+	if ok {
+		return state
+	}
+	return s.new(guildID)
+}
+
+// Set sets the state of the given guild.
+func (s *GuildState[T]) Set(guildID snowflake.ID, state T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[guildID] = state
+}
+
+// Update atomically replaces the state of the given guild with the state returned by updateFunc and returns it.
+func (s *GuildState[T]) Update(guildID snowflake.ID, updateFunc func(state T) T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[guildID]
+	if !ok {
+		state = s.new(guildID)
+	}
+	state = updateFunc(state)
+	s.states[guildID] = state
+	return state
+}
+
+// Delete removes the state of the given guild.
+func (s *GuildState[T]) Delete(guildID snowflake.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, guildID)
+}
+
+// ForEach calls the given function for the state of each guild.
+func (s *GuildState[T]) ForEach(fn func(guildID snowflake.ID, state T)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for guildID, state := range s.states {
+		fn(guildID, state)
+	}
+}
+
+func (s *GuildState[T]) new(guildID snowflake.ID) T {
+	if s.newFunc == nil {
+		var state T
+		return state
+	}
+	return s.newFunc(guildID)
+}
@@ -0,0 +1,396 @@
+// Package plugin provides a way to split a bot into plugins (modules) like moderation, music or tickets.
+//
+// Each plugin registers its own commands, handler routes and event listeners in Plugin.Init and can start and stop background work in Plugin.Start and Plugin.Stop.
+// Plugins can depend on other plugins, which are always initialized and started before and stopped after the plugins depending on them.
+// Every plugin can be enabled or disabled per guild at runtime. Events and interactions of guilds a plugin is disabled in are not passed to the plugin.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var (
+	// ErrPluginNotFound is returned when a plugin with the given name is not registered.
+	ErrPluginNotFound = errors.New("plugin not found")
+	// ErrPluginAlreadyRegistered is returned when a plugin with the same name is already registered.
+	ErrPluginAlreadyRegistered = errors.New("plugin already registered")
+	// ErrMissingDependency is returned when a dependency of a plugin is not registered.
+	ErrMissingDependency = errors.New("missing plugin dependency")
+	// ErrDependencyCycle is returned when plugins depend on each other.
+	ErrDependencyCycle = errors.New("plugin dependency cycle")
+	// ErrAlreadyInitialized is returned when plugins are registered after the Manager was initialized.
+	ErrAlreadyInitialized = errors.New("plugin manager already initialized")
+)
+
+// Plugin is a self-contained feature of a bot.
+type Plugin interface {
+	// Name returns the unique name of the plugin.
+	Name() string
+
+	// Init registers the commands, routes and event listeners of the plugin.
+	Init(setup *Setup) error
+
+	// Start starts the plugin after all plugins have been initialized.
+	Start(ctx context.Context) error
+
+	// Stop stops the plugin.
+	Stop(ctx context.Context) error
+}
+
+// DependentPlugin is a Plugin which depends on other plugins.
+type DependentPlugin interface {
+	Plugin
+
+	// Dependencies returns the names of the plugins this plugin depends on.
+	Dependencies() []string
+}
+
+// Setup is passed to Plugin.Init and is used to register the commands, routes and event listeners of a plugin.
+type Setup struct {
+	// Client is the bot.Client the plugin is running on.
+	Client *bot.Client
+	// Router is the handler.Router of the plugin. Interactions of guilds the plugin is disabled in are not routed to it.
+	Router handler.Router
+
+	manager *Manager
+	state   *pluginState
+}
+
+// AddCommands adds the given commands to the commands of the plugin which are synced with Manager.SyncCommands.
+func (s *Setup) AddCommands(commands ...discord.ApplicationCommandCreate) {
+	s.state.commands = append(s.state.commands, commands...)
+}
+
+// AddEventListeners adds the given bot.EventListener(s) to the plugin. They are only called for events of guilds the plugin is enabled in.
+func (s *Setup) AddEventListeners(listeners ...bot.EventListener) {
+	for _, listener := range listeners {
+		s.state.listeners = append(s.state.listeners, &guildListener{
+			listener: listener,
+			state:    s.state,
+		})
+	}
+}
+
+// Plugin returns the already initialized plugin with the given name. This is useful to access dependencies.
+func (s *Setup) Plugin(name string) (Plugin, bool) {
+	return s.manager.Plugin(name)
+}
+
+// New returns a new Manager for the given bot.Client with the given ConfigOpt(s) applied.
+func New(client *bot.Client, opts ...ConfigOpt) *Manager {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Manager{
+		client: client,
+		config: cfg,
+		states: map[string]*pluginState{},
+	}
+}
+
+// Manager initializes, starts and stops plugins in the order of their dependencies.
+type Manager struct {
+	client *bot.Client
+	config config
+
+	mu          sync.RWMutex
+	initialized bool
+	order       []*pluginState
+	states      map[string]*pluginState
+}
+
+type pluginState struct {
+	plugin    Plugin
+	router    *handler.Mux
+	commands  []discord.ApplicationCommandCreate
+	listeners []bot.EventListener
+	// stopped is set by Manager.Stop, so interactions and events are no longer passed to the plugin
+	stopped atomic.Bool
+
+	disabledMu     sync.RWMutex
+	disabledGuilds map[snowflake.ID]struct{}
+}
+
+func (s *pluginState) enabled(guildID snowflake.ID) bool {
+	s.disabledMu.RLock()
+	defer s.disabledMu.RUnlock()
+	_, disabled := s.disabledGuilds[guildID]
+	return !disabled
+}
+
+// Register registers the given plugins. Plugins need to be registered before calling Init.
+func (m *Manager) Register(plugins ...Plugin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.initialized {
+		return ErrAlreadyInitialized
+	}
+	for _, p := range plugins {
+		if _, ok := m.states[p.Name()]; ok {
+			return fmt.Errorf("%w: %s", ErrPluginAlreadyRegistered, p.Name())
+		}
+		m.states[p.Name()] = &pluginState{
+			plugin:         p,
+			disabledGuilds: map[snowflake.ID]struct{}{},
+		}
+		m.order = append(m.order, m.states[p.Name()])
+	}
+	return nil
+}
+
+// Init sorts all registered plugins by their dependencies and initializes them.
+// Afterward all event listeners of the plugins are added to the bot.Client and all routes are mounted to the handler.Router.
+func (m *Manager) Init() error {
+	m.mu.Lock()
+	if m.initialized {
+		m.mu.Unlock()
+		return ErrAlreadyInitialized
+	}
+	order, err := m.sort()
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	m.order = order
+	m.initialized = true
+	m.mu.Unlock()
+
+	router := m.config.Router
+	if router == nil {
+		mux := handler.New()
+		router = mux
+		m.client.AddEventListeners(mux)
+	}
+
+	for _, state := range order {
+		state.router = handler.New()
+		state.router.Use(m.guildMiddleware(state))
+		if err = state.plugin.Init(&Setup{
+			Client:  m.client,
+			Router:  state.router,
+			manager: m,
+			state:   state,
+		}); err != nil {
+			return fmt.Errorf("failed to init plugin %s: %w", state.plugin.Name(), err)
+		}
+		router.Mount("", state.router)
+		m.client.AddEventListeners(state.listeners...)
+		m.config.Logger.Debug("initialized plugin", slog.String("plugin", state.plugin.Name()))
+	}
+	return nil
+}
+
+// Start starts all plugins in the order of their dependencies.
+// If a plugin fails to start, all already started plugins are stopped again.
+func (m *Manager) Start(ctx context.Context) error {
+	order := m.plugins()
+	for i, state := range order {
+		if err := state.plugin.Start(ctx); err != nil {
+			state.stopped.Store(true)
+			for j := i - 1; j >= 0; j-- {
+				order[j].stopped.Store(true)
+				if stopErr := order[j].plugin.Stop(ctx); stopErr != nil {
+					m.config.Logger.Error("failed to stop plugin", slog.String("plugin", order[j].plugin.Name()), slog.Any("err", stopErr))
+				}
+			}
+			return fmt.Errorf("failed to start plugin %s: %w", state.plugin.Name(), err)
+		}
+		state.stopped.Store(false)
+		m.config.Logger.Debug("started plugin", slog.String("plugin", state.plugin.Name()))
+	}
+	return nil
+}
+
+// Stop stops all plugins in the reverse order of their dependencies.
+// Events are no longer passed to the event listeners of stopped plugins and their interactions are passed to the handler set with WithDisabledHandler until they are started again.
+func (m *Manager) Stop(ctx context.Context) error {
+	order := m.plugins()
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		state := order[i]
+		state.stopped.Store(true)
+		if err := state.plugin.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop plugin %s: %w", state.plugin.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Plugin returns the plugin with the given name.
+func (m *Manager) Plugin(name string) (Plugin, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.states[name]
+	if !ok {
+		return nil, false
+	}
+	return state.plugin, true
+}
+
+// Commands returns the commands of all plugins.
+func (m *Manager) Commands() []discord.ApplicationCommandCreate {
+	var commands []discord.ApplicationCommandCreate
+	for _, state := range m.plugins() {
+		commands = append(commands, state.commands...)
+	}
+	return commands
+}
+
+// SyncCommands syncs the commands of all plugins via handler.SyncCommands.
+func (m *Manager) SyncCommands(guildIDs []snowflake.ID, opts ...rest.RequestOpt) error {
+	return handler.SyncCommands(m.client, m.Commands(), guildIDs, opts...)
+}
+
+// EnableGuild enables the plugin with the given name in the given guild. Plugins are enabled in all guilds by default.
+func (m *Manager) EnableGuild(name string, guildID snowflake.ID) error {
+	state, err := m.state(name)
+	if err != nil {
+		return err
+	}
+	state.disabledMu.Lock()
+	defer state.disabledMu.Unlock()
+	delete(state.disabledGuilds, guildID)
+	return nil
+}
+
+// DisableGuild disables the plugin with the given name in the given guild.
+func (m *Manager) DisableGuild(name string, guildID snowflake.ID) error {
+	state, err := m.state(name)
+	if err != nil {
+		return err
+	}
+	state.disabledMu.Lock()
+	defer state.disabledMu.Unlock()
+	state.disabledGuilds[guildID] = struct{}{}
+	return nil
+}
+
+// Enabled returns whether the plugin with the given name is enabled in the given guild.
+func (m *Manager) Enabled(name string, guildID snowflake.ID) bool {
+	state, err := m.state(name)
+	if err != nil {
+		return false
+	}
+	return state.enabled(guildID)
+}
+
+func (m *Manager) state(name string) (*pluginState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.states[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+	}
+	return state, nil
+}
+
+func (m *Manager) plugins() []*pluginState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.order
+}
+
+// sort returns the registered plugins sorted by their dependencies while keeping the registration order where possible.
+func (m *Manager) sort() ([]*pluginState, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(m.order))
+	sorted := make([]*pluginState, 0, len(m.order))
+
+	var visit func(state *pluginState) error
+	visit = func(state *pluginState) error {
+		name := state.plugin.Name()
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, name)
+		}
+		marks[name] = visiting
+		if dp, ok := state.plugin.(DependentPlugin); ok {
+			for _, dependency := range dp.Dependencies() {
+				dependencyState, ok := m.states[dependency]
+				if !ok {
+					return fmt.Errorf("%w: %s depends on %s", ErrMissingDependency, name, dependency)
+				}
+				if err := visit(dependencyState); err != nil {
+					return err
+				}
+			}
+		}
+		marks[name] = visited
+		sorted = append(sorted, state)
+		return nil
+	}
+
+	for _, state := range m.order {
+		if err := visit(state); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+func (m *Manager) guildMiddleware(state *pluginState) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		return func(e *handler.InteractionEvent) error {
+			if state.stopped.Load() {
+				return m.config.DisabledHandler(e)
+			}
+			if guildID := e.GuildID(); guildID != nil && !state.enabled(*guildID) {
+				return m.config.DisabledHandler(e)
+			}
+			return next(e)
+		}
+	}
+}
+
+var (
+	_ bot.TypedEventListener    = (*guildListener)(nil)
+	_ bot.PriorityEventListener = (*guildListener)(nil)
+)
+
+// guildListener only passes events of guilds the plugin is enabled in to the wrapped bot.EventListener while the plugin is not stopped.
+type guildListener struct {
+	listener bot.EventListener
+	state    *pluginState
+}
+
+func (l *guildListener) OnEvent(event bot.Event) {
+	if l.state.stopped.Load() {
+		return
+	}
+	if guildID, ok := bot.EventGuildID(event); ok && !l.state.enabled(guildID) {
+		return
+	}
+	l.listener.OnEvent(event)
+}
+
+func (l *guildListener) EventType() reflect.Type {
+	if tl, ok := l.listener.(bot.TypedEventListener); ok {
+		return tl.EventType()
+	}
+	return nil
+}
+
+func (l *guildListener) Priority() int {
+	if pl, ok := l.listener.(bot.PriorityEventListener); ok {
+		return pl.Priority()
+	}
+	return 0
+}
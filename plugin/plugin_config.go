@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger: slog.Default(),
+		DisabledHandler: func(e *handler.InteractionEvent) error {
+			if e.Type() == discord.InteractionTypeAutocomplete {
+				return e.AutocompleteResult(nil)
+			}
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This feature is disabled in this server.",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		},
+	}
+}
+
+type config struct {
+	Logger          *slog.Logger
+	Router          handler.Router
+	DisabledHandler handler.Handler
+}
+
+// ConfigOpt is a type alias for a function that takes a config and is used to configure your Manager.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "plugin_manager"))
+}
+
+// WithLogger lets you inject your own logger implementing *slog.Logger.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithRouter sets the handler.Router all plugin routes are mounted to.
+// If not set, the Manager creates its own handler.Mux and adds it as bot.EventListener to the bot.Client.
+func WithRouter(router handler.Router) ConfigOpt {
+	return func(config *config) {
+		config.Router = router
+	}
+}
+
+// WithDisabledHandler sets the handler.Handler which is called for interactions of a plugin which is disabled in the guild or has been stopped.
+// By default, an ephemeral message is sent.
+func WithDisabledHandler(h handler.Handler) ConfigOpt {
+	return func(config *config) {
+		config.DisabledHandler = h
+	}
+}
@@ -0,0 +1,67 @@
+package plugin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/plugin"
+)
+
+type testEvent struct{}
+
+func (e *testEvent) Client() *bot.Client   { return nil }
+func (e *testEvent) SequenceNumber() int   { return 0 }
+func (e *testEvent) Cancel()               {}
+func (e *testEvent) CancelCause(err error) {}
+func (e *testEvent) IsCanceled() bool      { return false }
+
+type testPlugin struct {
+	events int
+}
+
+func (p *testPlugin) Name() string { return "test" }
+
+func (p *testPlugin) Init(setup *plugin.Setup) error {
+	setup.AddEventListeners(bot.NewListenerFunc(func(e *testEvent) {
+		p.events++
+	}))
+	return nil
+}
+
+func (p *testPlugin) Start(ctx context.Context) error { return nil }
+
+func (p *testPlugin) Stop(ctx context.Context) error { return nil }
+
+func TestManager_StopStart(t *testing.T) {
+	client := &bot.Client{}
+	client.EventManager = bot.NewEventManager(client)
+	p := &testPlugin{}
+	m := plugin.New(client, plugin.WithRouter(handler.New()))
+	if err := m.Register(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name     string
+		action   func() error
+		expected int
+	}{
+		{name: "start", action: func() error { return m.Start(context.Background()) }, expected: 1},
+		{name: "stop", action: func() error { return m.Stop(context.Background()) }, expected: 1},
+		{name: "restart", action: func() error { return m.Start(context.Background()) }, expected: 2},
+	}
+	for _, s := range steps {
+		if err := s.action(); err != nil {
+			t.Fatalf("%s: %s", s.name, err)
+		}
+		client.EventManager.DispatchEvent(&testEvent{})
+		if p.events != s.expected {
+			t.Errorf("%s: expected %d events, got %d", s.name, s.expected, p.events)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package reactionroles maps reactions and buttons on messages to roles.
+//
+// A Binding connects a reaction emoji or a button custom ID on a message to a role.
+// Members get the role when they react or click the button and lose it when they remove their reaction or click the button again.
+// Bindings can be put into exclusive groups, so a member can only have one role of the group at a time.
+//
+// The Manager is a bot.EventListener and needs to be added to the bot.Client. Reaction roles require the gateway.IntentGuildMessageReactions.
+package reactionroles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+var _ bot.EventListener = (*Manager)(nil)
+
+// ErrInvalidBinding is returned when a Binding has neither or both an Emoji and a CustomID.
+var ErrInvalidBinding = errors.New("binding must have either an emoji or a custom id")
+
+// Binding maps a reaction or a button on a message to a role.
+type Binding struct {
+	GuildID   snowflake.ID `json:"guild_id"`
+	ChannelID snowflake.ID `json:"channel_id"`
+	MessageID snowflake.ID `json:"message_id"`
+	// Emoji is the reaction in the format of discord.PartialEmoji.Reaction. Either Emoji or CustomID must be set.
+	Emoji string `json:"emoji,omitempty"`
+	// CustomID is the custom ID of the button. Either Emoji or CustomID must be set.
+	CustomID string       `json:"custom_id,omitempty"`
+	RoleID   snowflake.ID `json:"role_id"`
+	// Group is the name of the exclusive group of the Binding. Members can only have one role of all Binding(s) of a message with the same Group.
+	Group string `json:"group,omitempty"`
+}
+
+type bindingKey struct {
+	messageID snowflake.ID
+	emoji     string
+	customID  string
+}
+
+func (b Binding) key() bindingKey {
+	return bindingKey{
+		messageID: b.MessageID,
+		emoji:     b.Emoji,
+		customID:  b.CustomID,
+	}
+}
+
+// New returns a new Manager with the given ConfigOpt(s) applied.
+func New(client *bot.Client, opts ...ConfigOpt) *Manager {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	return &Manager{
+		client:   client,
+		config:   cfg,
+		bindings: map[snowflake.ID][]Binding{},
+	}
+}
+
+// Manager grants and revokes roles for reactions and button clicks.
+type Manager struct {
+	client *bot.Client
+	config config
+
+	mu       sync.RWMutex
+	bindings map[snowflake.ID][]Binding
+}
+
+// Load loads all Binding(s) from the Store.
+func (m *Manager) Load(ctx context.Context) error {
+	bindings, err := m.config.Store.All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load reaction role bindings: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bindings = map[snowflake.ID][]Binding{}
+	for _, binding := range bindings {
+		m.bindings[binding.MessageID] = append(m.bindings[binding.MessageID], binding)
+	}
+	return nil
+}
+
+// Bind adds the given Binding and saves it in the Store. An existing Binding with the same message and emoji or custom ID is replaced.
+func (m *Manager) Bind(ctx context.Context, binding Binding) error {
+	if (binding.Emoji == "") == (binding.CustomID == "") {
+		return ErrInvalidBinding
+	}
+	if err := m.config.Store.Save(ctx, binding); err != nil {
+		return fmt.Errorf("failed to save reaction role binding: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bindings := slices.DeleteFunc(m.bindings[binding.MessageID], func(b Binding) bool {
+		return b.key() == binding.key()
+	})
+	m.bindings[binding.MessageID] = append(bindings, binding)
+	return nil
+}
+
+// Unbind removes the given Binding and deletes it from the Store.
+func (m *Manager) Unbind(ctx context.Context, binding Binding) error {
+	if err := m.config.Store.Delete(ctx, binding); err != nil {
+		return fmt.Errorf("failed to delete reaction role binding: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bindings := slices.DeleteFunc(m.bindings[binding.MessageID], func(b Binding) bool {
+		return b.key() == binding.key()
+	})
+	if len(bindings) == 0 {
+		delete(m.bindings, binding.MessageID)
+		return nil
+	}
+	m.bindings[binding.MessageID] = bindings
+	return nil
+}
+
+// Bindings returns all Binding(s) of the given message.
+func (m *Manager) Bindings(messageID snowflake.ID) []Binding {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.bindings[messageID])
+}
+
+// Reconcile reads the reactions of all reaction Binding(s) via rest.Channels.GetReactions and grants the bound role to every member who reacted while the bot was offline.
+// Roles are not revoked from members who removed their reaction in the meantime, as this would require fetching all members of the guild.
+func (m *Manager) Reconcile(ctx context.Context) error {
+	m.mu.RLock()
+	var bindings []Binding
+	for _, messageBindings := range m.bindings {
+		for _, binding := range messageBindings {
+			if binding.Emoji != "" {
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, binding := range bindings {
+		if err := m.reconcileBinding(ctx, binding); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reconcile reaction role binding for message %s and emoji %s: %w", binding.MessageID, binding.Emoji, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) reconcileBinding(ctx context.Context, binding Binding) error {
+	var after int
+	for {
+		users, err := m.client.Rest.GetReactions(binding.ChannelID, binding.MessageID, binding.Emoji, discord.MessageReactionTypeNormal, after, 100, rest.WithCtx(ctx))
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if user.ID == m.client.ID() || (user.Bot && m.config.IgnoreBots) {
+				continue
+			}
+			if member, ok := m.client.Caches.Member(binding.GuildID, user.ID); ok && slices.Contains(member.RoleIDs, binding.RoleID) {
+				continue
+			}
+			if err = m.client.Rest.AddMemberRole(binding.GuildID, user.ID, binding.RoleID, rest.WithCtx(ctx)); err != nil {
+				m.config.Logger.Error("failed to add reaction role", slog.Any("err", err), slog.String("user_id", user.ID.String()), slog.String("role_id", binding.RoleID.String()))
+			}
+		}
+		if len(users) < 100 || ctx.Err() != nil {
+			return ctx.Err()
+		}
+		after = int(users[len(users)-1].ID)
+	}
+}
+
+// OnEvent handles reactions and button clicks of bound messages.
+func (m *Manager) OnEvent(event bot.Event) {
+	switch e := event.(type) {
+	case *events.GuildMessageReactionAdd:
+		m.onReactionAdd(e)
+	case *events.GuildMessageReactionRemove:
+		m.onReactionRemove(e)
+	case *events.ComponentInteractionCreate:
+		m.onComponentInteraction(e)
+	}
+}
+
+func (m *Manager) find(messageID snowflake.ID, match func(b Binding) bool) (Binding, []Binding, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	bindings := m.bindings[messageID]
+	index := slices.IndexFunc(bindings, match)
+	if index == -1 {
+		return Binding{}, nil, false
+	}
+	return bindings[index], slices.Clone(bindings), true
+}
+
+func (m *Manager) onReactionAdd(e *events.GuildMessageReactionAdd) {
+	if e.UserID == m.client.ID() || (e.Member.User.Bot && m.config.IgnoreBots) {
+		return
+	}
+	reaction := e.Emoji.Reaction()
+	binding, bindings, ok := m.find(e.MessageID, func(b Binding) bool {
+		return b.Emoji != "" && b.Emoji == reaction
+	})
+	if !ok {
+		return
+	}
+
+	for _, other := range m.exclusive(binding, bindings) {
+		if slices.Contains(e.Member.RoleIDs, other.RoleID) {
+			m.removeRole(binding.GuildID, e.UserID, other.RoleID)
+		}
+		if other.Emoji != "" && m.config.RemoveExclusiveReactions {
+			if err := e.Client().Rest.RemoveUserReaction(e.ChannelID, e.MessageID, other.Emoji, e.UserID); err != nil {
+				m.config.Logger.Error("failed to remove exclusive reaction", slog.Any("err", err), slog.String("emoji", other.Emoji))
+			}
+		}
+	}
+	if !slices.Contains(e.Member.RoleIDs, binding.RoleID) {
+		m.addRole(binding.GuildID, e.UserID, binding.RoleID)
+	}
+}
+
+func (m *Manager) onReactionRemove(e *events.GuildMessageReactionRemove) {
+	if e.UserID == m.client.ID() {
+		return
+	}
+	reaction := e.Emoji.Reaction()
+	binding, _, ok := m.find(e.MessageID, func(b Binding) bool {
+		return b.Emoji != "" && b.Emoji == reaction
+	})
+	if !ok {
+		return
+	}
+	m.removeRole(binding.GuildID, e.UserID, binding.RoleID)
+}
+
+func (m *Manager) onComponentInteraction(e *events.ComponentInteractionCreate) {
+	member := e.Member()
+	if member == nil || (member.User.Bot && m.config.IgnoreBots) {
+		return
+	}
+	customID := e.Data.CustomID()
+	binding, bindings, ok := m.find(e.Message.ID, func(b Binding) bool {
+		return b.CustomID != "" && b.CustomID == customID
+	})
+	if !ok {
+		return
+	}
+
+	added := !slices.Contains(member.RoleIDs, binding.RoleID)
+	if added {
+		for _, other := range m.exclusive(binding, bindings) {
+			if slices.Contains(member.RoleIDs, other.RoleID) {
+				m.removeRole(binding.GuildID, member.User.ID, other.RoleID)
+			}
+		}
+		m.addRole(binding.GuildID, member.User.ID, binding.RoleID)
+	} else {
+		m.removeRole(binding.GuildID, member.User.ID, binding.RoleID)
+	}
+
+	if err := e.CreateMessage(m.config.ButtonResponseMessageFunc(binding, added)); err != nil {
+		m.config.Logger.Error("failed to respond to button role interaction", slog.Any("err", err))
+	}
+}
+
+// exclusive returns all other Binding(s) in the exclusive group of the given Binding.
+func (m *Manager) exclusive(binding Binding, bindings []Binding) []Binding {
+	if binding.Group == "" {
+		return nil
+	}
+	var others []Binding
+	for _, other := range bindings {
+		if other.Group == binding.Group && other.key() != binding.key() && other.RoleID != binding.RoleID {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+func (m *Manager) addRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID) {
+	if err := m.client.Rest.AddMemberRole(guildID, userID, roleID); err != nil {
+		m.config.Logger.Error("failed to add reaction role", slog.Any("err", err), slog.String("user_id", userID.String()), slog.String("role_id", roleID.String()))
+	}
+}
+
+func (m *Manager) removeRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID) {
+	if err := m.client.Rest.RemoveMemberRole(guildID, userID, roleID); err != nil {
+		m.config.Logger.Error("failed to remove reaction role", slog.Any("err", err), slog.String("user_id", userID.String()), slog.String("role_id", roleID.String()))
+	}
+}
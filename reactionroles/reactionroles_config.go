@@ -0,0 +1,85 @@
+package reactionroles
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger:                    slog.Default(),
+		Store:                     NewMemoryStore(),
+		RemoveExclusiveReactions:  true,
+		IgnoreBots:                true,
+		ButtonResponseMessageFunc: defaultButtonResponseMessage,
+	}
+}
+
+func defaultButtonResponseMessage(binding Binding, added bool) discord.MessageCreate {
+	content := fmt.Sprintf("Removed the %s role.", discord.RoleMention(binding.RoleID))
+	if added {
+		content = fmt.Sprintf("Added the %s role.", discord.RoleMention(binding.RoleID))
+	}
+	return discord.MessageCreate{
+		Content:         content,
+		Flags:           discord.MessageFlagEphemeral,
+		AllowedMentions: &discord.AllowedMentions{},
+	}
+}
+
+type config struct {
+	Logger                    *slog.Logger
+	Store                     Store
+	RemoveExclusiveReactions  bool
+	IgnoreBots                bool
+	ButtonResponseMessageFunc func(binding Binding, added bool) discord.MessageCreate
+}
+
+// ConfigOpt is a type alias for a function that takes a config and is used to configure your Manager.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "reaction_roles"))
+}
+
+// WithLogger lets you inject your own logger implementing *slog.Logger.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithStore sets the Store the Manager persists its Binding(s) in. By default, Binding(s) are only kept in memory.
+func WithStore(store Store) ConfigOpt {
+	return func(config *config) {
+		config.Store = store
+	}
+}
+
+// WithRemoveExclusiveReactions sets whether the other reactions of a member in an exclusive group are removed when the member reacts.
+// This is enabled by default.
+func WithRemoveExclusiveReactions(remove bool) ConfigOpt {
+	return func(config *config) {
+		config.RemoveExclusiveReactions = remove
+	}
+}
+
+// WithIgnoreBots sets whether reactions and button clicks of bots are ignored. This is enabled by default.
+func WithIgnoreBots(ignore bool) ConfigOpt {
+	return func(config *config) {
+		config.IgnoreBots = ignore
+	}
+}
+
+// WithButtonResponseMessageFunc sets the func which creates the response to a button role click.
+// By default, an ephemeral message mentioning the added or removed role is sent.
+func WithButtonResponseMessageFunc(f func(binding Binding, added bool) discord.MessageCreate) ConfigOpt {
+	return func(config *config) {
+		config.ButtonResponseMessageFunc = f
+	}
+}
@@ -0,0 +1,156 @@
+package reactionroles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+var (
+	_ Store = (*memoryStore)(nil)
+	_ Store = (*fileStore)(nil)
+)
+
+// Store persists the Binding(s) of a Manager.
+type Store interface {
+	// Save inserts the given Binding or replaces the Binding with the same key.
+	Save(ctx context.Context, binding Binding) error
+
+	// Delete removes the Binding with the same key as the given Binding. Deleting a Binding which does not exist is not an error.
+	Delete(ctx context.Context, binding Binding) error
+
+	// All returns all stored Binding(s).
+	All(ctx context.Context) ([]Binding, error)
+}
+
+// NewMemoryStore returns a Store which keeps all Binding(s) in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		bindings: map[bindingKey]Binding{},
+	}
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	bindings map[bindingKey]Binding
+}
+
+func (s *memoryStore) Save(_ context.Context, binding Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bindings[binding.key()] = binding
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, binding Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bindings, binding.key())
+	return nil
+}
+
+func (s *memoryStore) All(_ context.Context) ([]Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bindings := make([]Binding, 0, len(s.bindings))
+	for _, binding := range s.bindings {
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+// NewFileStore returns a Store which keeps all Binding(s) in the given JSON file.
+// The file is created if it does not exist and replaced atomically on every change.
+func NewFileStore(path string) Store {
+	return &fileStore{
+		path: path,
+	}
+}
+
+type fileStore struct {
+	path string
+
+	mu sync.Mutex
+}
+
+func (s *fileStore) Save(_ context.Context, binding Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bindings, err := s.read()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(bindings, func(b Binding) bool {
+		return b.key() == binding.key()
+	})
+	if index == -1 {
+		bindings = append(bindings, binding)
+	} else {
+		bindings[index] = binding
+	}
+	return s.write(bindings)
+}
+
+func (s *fileStore) Delete(_ context.Context, binding Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bindings, err := s.read()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(bindings, func(b Binding) bool {
+		return b.key() == binding.key()
+	})
+	if index == -1 {
+		return nil
+	}
+	return s.write(slices.Delete(bindings, index, index+1))
+}
+
+func (s *fileStore) All(_ context.Context) ([]Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *fileStore) read() ([]Binding, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var bindings []Binding
+	if err = json.Unmarshal(data, &bindings); err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+func (s *fileStore) write(bindings []Binding) error {
+	data, err := json.Marshal(bindings)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.path)
+}